@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/cadence"
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// updateAnalogLibraries regenerates the icw block of local.lib from all
+// analog components and makes sure cds.lib includes it
+func updateAnalogLibraries(root string, comps []*component.Component) {
+	localLib := filepath.Join(root, "local.lib")
+
+	libs := cadence.LibrariesFromComponents(comps)
+	if len(libs) == 0 {
+		// Nothing to define, leave workspaces without analog parts alone
+		if _, err := os.Stat(localLib); os.IsNotExist(err) {
+			return
+		}
+	}
+
+	warnings, err := cadence.WriteLocalLib(localLib, libs)
+	if err != nil {
+		color.Red("  Failed to update local.lib: %v", err)
+		return
+	}
+	color.Cyan("  [LIBS] local.lib: %d library definition(s)", len(libs))
+	for _, warning := range warnings {
+		color.Yellow("    Warning: %s", warning)
+	}
+
+	changed, err := cadence.EnsureInclude(filepath.Join(root, "cds.lib"), "local.lib")
+	if err != nil {
+		color.Red("  Failed to update cds.lib: %v", err)
+		return
+	}
+	if changed {
+		color.Cyan("  [LIBS] cds.lib: added SOFTINCLUDE local.lib")
+	}
+}
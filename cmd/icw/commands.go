@@ -1,16 +1,21 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/jakobsen/icw/internal/component"
 	"github.com/jakobsen/icw/internal/config"
 	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/jakobsen/icw/internal/hook"
+	"github.com/jakobsen/icw/internal/semver"
 	"github.com/jakobsen/icw/internal/svn"
 	"github.com/jakobsen/icw/internal/version"
 	"github.com/spf13/cobra"
@@ -34,6 +39,26 @@ func init() {
 	listCmd.Flags().BoolP("tags", "g", false, "Show tags for component")
 	listCmd.Flags().BoolP("all", "a", false, "Show all details (branches and tags)")
 	listCmd.Flags().StringP("repo", "r", "", "Repository to list from (overrides ICW_REPO/workspace.config)")
+
+	// Add flags for update command
+	updateCmd.Flags().BoolVar(&flagUpdateStash, "stash", false, "Stash local modifications before switching branches")
+	updateCmd.Flags().BoolVar(&flagUpdatePrune, "prune", false, "Remove components that are no longer declared")
+	updateCmd.Flags().BoolVar(&flagUpdateNoHooks, "no-hooks", false, "Do not run post-checkout/post-update hooks")
+	updateCmd.Flags().DurationVar(&flagUpdateHookTimeout, "hook-timeout", hook.DefaultTimeout, "Maximum run time of a single hook")
+
+	// Add flags for hdl command
+	hdlCmd.Flags().StringVar(&flagHdlFlow, "flow", "", "Only show files used by a flow (synthesis, rtl-sim, gate-sim)")
+	hdlCmd.Flags().BoolVar(&flagHdlList, "list", false, "Print a flat file list for --flow in compile order")
+	hdlCmd.Flags().BoolVar(&flagHdlOptions, "options", false, "Start --list with +incdir+ and +define+ lines")
+	hdlCmd.Flags().BoolVar(&flagHdlStrict, "strict", false, "Exit with an error if design units are defined more than once")
+	hdlCmd.Flags().StringVar(&flagHdlManifest, "manifest", "", "Write a manifest of the HDL files with content hashes to a file (- for stdout)")
+
+	// Add flags for tree command
+	treeCmd.Flags().BoolVarP(&flagTreeLong, "long", "l", false, "Show component metadata (owner, maturity, nodes, license)")
+
+	// Add flags for status command
+	statusCmd.Flags().BoolVarP(&flagStatusInteractive, "interactive", "i", false, "Interactively add, ignore or revert changed files")
+	statusCmd.Flags().BoolVar(&flagStatusOffline, "offline", false, "Do not compare with the repository")
 }
 
 var versionCmd = &cobra.Command{
@@ -48,12 +73,41 @@ var versionCmd = &cobra.Command{
 var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Sync workspace with repository (checkout components)",
-	Long:  `Updates the workspace by checking out components from the repository.`,
+	Long: `Updates the workspace by checking out components from the repository.
+
+When the branch declared for a component no longer matches its working copy,
+the working copy is switched to the declared branch. Components with local
+modifications are not switched unless --stash is given, which saves the
+changes as a patch under .icw/stash, together with an archive of the changed
+files for binary content such as Cadence views, and reverts them before
+switching.
+
+With --prune, working copies that are no longer part of the dependency tree
+are removed afterwards (see 'icw prune').
+
+Hooks run after checkout/update. A component declares them in depend.config,
+and workspace.config can declare them for all components of a type:
+
+  hook post_update "make -C regs"              # depend.config
+  hook setup post_checkout "./install.sh"      # workspace.config
+
+Hooks run with sh in the component directory with ICW_ROOT, ICW_COMPONENT,
+ICW_BRANCH and ICW_TYPE set. Hooks from components must be trusted first;
+you are asked before an untrusted hook runs, and again when its command or
+a script it runs changes. Use --no-hooks to skip them.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runUpdate()
 	},
 }
 
+// Command flags
+var (
+	flagUpdateStash       bool
+	flagUpdatePrune       bool
+	flagUpdateNoHooks     bool
+	flagUpdateHookTimeout time.Duration
+)
+
 func runUpdate() error {
 	// Find workspace root
 	root, err := config.FindWorkspaceRoot()
@@ -109,6 +163,11 @@ func runUpdate() error {
 		color.Cyan("  (from workspace.config)")
 	}
 
+	// A second process component is an error, so check before checking out
+	if _, err := processComponent(ws.Ordered()); err != nil {
+		return err
+	}
+
 	// Collect components to process (including dependencies)
 	// We'll use a queue to process components in order
 	processQueue := make([]*component.Component, 0)
@@ -118,6 +177,11 @@ func runUpdate() error {
 
 	// Track components we've already checked out to avoid duplicates
 	checkedOut := make(map[string]bool)
+	failed := 0
+
+	// Components updated in this run, for hooks; fresh marks first checkouts
+	var updated []*component.Component
+	fresh := make(map[string]bool)
 
 	// Process components from the queue
 	for len(processQueue) > 0 {
@@ -139,32 +203,73 @@ func runUpdate() error {
 
 		destPath := filepath.Join(root, comp.Path)
 
+		// Process components declared by dependencies are known by now
+		if comp.Type == component.TypeProcess {
+			if _, err := processComponent(ws.Ordered()); err != nil {
+				return err
+			}
+		}
+
 		if comp.VCS == "svn" {
 			// Check if already checked out
 			if svn.IsWorkingCopy(destPath) {
-				color.Yellow("  [UPDATE] %s (%s)", comp.Name, comp.Branch)
-				if err := svnClient.Update(destPath); err != nil {
+				// Follow branch changes in workspace.config or depend.config
+				url, err := svnClient.WorkingCopyURL(destPath)
+				if err != nil {
 					color.Red("    Failed: %v", err)
+					failed++
 					continue
 				}
+				branch, known := svnClient.BranchFromURL(comp.Path, url)
+
+				if !known {
+					// Checked out from elsewhere, e.g. another server URL
+					color.Yellow("  [UPDATE] %s (%s)", comp.Name, url)
+					color.Yellow("    Warning: not checked out from %s, not switching to %s", svnClient.ComponentURL(comp.Path, ""), comp.Branch)
+					if err := svnClient.Update(destPath); err != nil {
+						color.Red("    Failed: %v", err)
+						failed++
+						continue
+					}
+				} else if branch != comp.Branch {
+					if err := switchComponent(comp, branch, root, destPath, svnClient); err != nil {
+						color.Red("    %v", err)
+						failed++
+						continue
+					}
+				} else {
+					color.Yellow("  [UPDATE] %s (%s)", comp.Name, comp.Branch)
+					if err := svnClient.Update(destPath); err != nil {
+						color.Red("    Failed: %v", err)
+						failed++
+						continue
+					}
+				}
 			} else {
 				color.Green("  [CHECKOUT] %s (%s)", comp.Name, comp.Branch)
 				// Create parent directory if needed
 				parentDir := filepath.Dir(destPath)
 				if err := os.MkdirAll(parentDir, 0755); err != nil {
 					color.Red("    Failed to create directory: %v", err)
+					failed++
 					continue
 				}
 
 				if err := svnClient.Checkout(comp.Path, comp.Branch, destPath); err != nil {
 					color.Red("    Failed: %v", err)
+					failed++
 					continue
 				}
+				fresh[comp.Name] = true
 			}
+			updated = append(updated, comp)
 
 			// Now check for depend.config and process dependencies
 			dependConfigPath := filepath.Join(destPath, "depend.config")
 			dependencies, err := parser.ParseDependConfig(comp, dependConfigPath)
+			for _, warning := range parser.TakeWarnings() {
+				color.Yellow("    Warning: %s", warning)
+			}
 			if err != nil {
 				// Check if it's a conflict error
 				if strings.Contains(err.Error(), "dependency conflict") || strings.Contains(err.Error(), "branch mismatch") {
@@ -172,6 +277,7 @@ func runUpdate() error {
 					return fmt.Errorf("version conflict detected: %w", err)
 				}
 				color.Red("    Warning: Failed to parse dependencies: %v", err)
+				failed++
 				continue
 			}
 
@@ -188,8 +294,29 @@ func runUpdate() error {
 		}
 	}
 
+	// Type-specific post-update steps; the process cds.lib is included
+	// before local.lib
+	if err := updateProcessSetup(root, ws.Ordered()); err != nil {
+		return err
+	}
+	updateAnalogLibraries(root, ws.Ordered())
+
+	if !flagUpdateNoHooks {
+		failed += runHooks(parser, root, updated, fresh)
+	}
+
 	color.Green("\nUpdate complete!")
 	color.Green("Processed %d component(s) total", len(checkedOut))
+
+	if flagUpdatePrune {
+		fmt.Println()
+		// An incomplete update means an incomplete dependency tree
+		if failed > 0 {
+			color.Yellow("Skipping prune: %d component(s) failed to update", failed)
+			return nil
+		}
+		return pruneWorkspace(root, ws.Ordered(), svnClient, false, false)
+	}
 	return nil
 }
 
@@ -197,15 +324,56 @@ var statusCmd = &cobra.Command{
 	Use:     "status",
 	Aliases: []string{"st"},
 	Short:   "Show status between workspace and repository",
+	Long: `Show the state of every component in the resolved dependency tree:
+
+  [MODIFIED]            local modifications in the working copy
+  [OUTDATED n revs]     n commits on the server since the last update
+  [WRONG BRANCH]        checked out branch differs from the declared one
+  [MISSING DEPENDENCY]  dependency is not checked out (run icw update)
+
+Use --offline to skip the comparison with the repository.
+
+With --interactive, entries are grouped per component by state (modified,
+unversioned, missing, conflicted) and you can add, ignore (svn:ignore),
+revert or skip them one file or one directory at a time. Unversioned
+Cadence and simulator artefacts are offered for svn:ignore in one step.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runStatus()
 	},
 }
 
+// Command flags
+var (
+	flagStatusInteractive bool
+	flagStatusOffline     bool
+)
+
+// Command flags
+var (
+	flagHdlFlow     string
+	flagHdlList     bool
+	flagHdlOptions  bool
+	flagHdlStrict   bool
+	flagHdlManifest string
+)
+
+// Command flags
+var (
+	flagTreeLong bool
+)
+
 var treeCmd = &cobra.Command{
 	Use:   "tree",
 	Short: "Display dependency tree from config files",
-	Long:  `Display component dependency tree showing workspace structure and component relationships based on workspace.config and depend.config files.`,
+	Long: `Display component dependency tree showing workspace structure and component relationships based on workspace.config and depend.config files.
+
+With --long the metadata of each component is shown as well: description,
+owner, maturity, process nodes and license from its component.info file or
+the meta block of its depend.config.
+
+Examples:
+  icw tree                             # Dependency tree
+  icw tree --long                      # With owners, maturity and nodes`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runTree()
 	},
@@ -214,7 +382,63 @@ var treeCmd = &cobra.Command{
 var hdlCmd = &cobra.Command{
 	Use:   "hdl",
 	Short: "Display dependency tree with HDL files",
-	Long:  `Display component dependency tree with detailed HDL file listings, categorized by type (package, rtl, behav).`,
+	Long: `Display component dependency tree with detailed HDL file listings, categorized by type (package, rtl, behav).
+
+HDL files (.v, .sv, .vh, .svh, .vhd, .vhdl) are searched recursively in each
+digital component. Hidden directories are skipped. A component can limit the
+search in its depend.config:
+
+  hdl_dirs "rtl" "tb"                 # Only search these directories
+  hdl_exclude "*_old.v" "rtl/unused/" # gitignore-style patterns
+
+A .icwignore file in any directory of a component lists further patterns,
+relative to that directory, with the same syntax as .gitignore.
+
+Files are classified as package, rtl, behav (*_tb, tb/), gate (*_gate,
+*_netlist), model (*_model, models/) or ip (ip/). Verilog files are also
+classified by content: a file with only packages is a package, and a program
+block, UVM or an initial block calling $finish make a testbench. A comment
+at the top of a file overrides this and can exclude it from a flow:
+
+  // icw: gate
+  // icw: exclude synthesis
+
+Flows (synthesis, rtl-sim, gate-sim) pick their files by type. depend.config
+can adjust this per file:
+
+  exclude synthesis "dig_top_cp3_gate.v"
+  include gate-sim "dig_top_cp3_gate.v"
+
+Compile settings are declared in depend.config as well. Include directories
+and defines also apply to every component using the component; a define of a
+using component replaces the one of its dependency:
+
+  incdir "include" "rtl/inc"          # Relative to the component
+  define SYNTHESIS                    # Define without a value
+  define DATA_WIDTH "32"              # Define with a value
+  library "spi_lib"                   # VHDL library (default: work)
+  top "spi_master"                    # Top-level unit of the component
+
+Examples:
+  icw hdl                              # All files by type
+  icw hdl --flow synthesis             # Files used for synthesis
+  icw hdl --flow gate-sim --list       # Flat file list, one path per line
+  icw hdl --flow rtl-sim --list --options > sim.f  # With +incdir+/+define+
+  icw hdl --manifest build/hdl.json    # Record file hashes and revisions
+  icw hdl diff build/hdl.json          # What changed since the manifest
+
+Files are listed in compile order: packages before the files that use them,
+and modules and entities before the files that instantiate them, across
+components. The order is the same on every run.
+
+Modules, entities, packages and interfaces defined in more than one file are
+reported as duplicates (see 'icw lint'); --strict makes this an error.
+
+--manifest records the SVN revision and the SHA-256 of every HDL file,
+depend.config and file in the include directories of each component, for
+--flow or all files. 'icw hdl diff'
+compares the workspace with such a manifest so that a build only recompiles
+the changed components and those depending on them.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runHdl()
 	},
@@ -380,6 +604,10 @@ var listCmd = &cobra.Command{
 	Short: "List components and their branches/tags",
 	Long: `List components in the repository with optional filtering and details.
 
+Tags are sorted as versions, so v1.9 comes before v1.10, and the newest
+release is marked as latest. Pre-releases such as v2.0-rc1 are only marked
+when there is no release.
+
 Examples:
   icw list                       # List all components
   icw list -t digital            # List only digital components
@@ -387,10 +615,14 @@ Examples:
   icw list -r cp4 -t analog      # List analog components from cp4
   icw list digital/my_module     # Show details for specific component
   icw list digital/my_module -b  # Show branches only
-  icw list digital/my_module -g  # Show tags only
+  icw list digital/my_module -g  # Show tags only, newest version last
   icw list digital/my_module -a  # Show all details (branches and tags)
   icw list digital/dig*          # Show all components matching pattern
-  icw list "digital/*cp3"        # Pattern with quotes (shell glob protection)`,
+  icw list "digital/*cp3"        # Pattern with quotes (shell glob protection)
+  icw list "*/spi_[ms]*"         # Patterns support *, ? and [...] in all types
+
+To search descriptions and owners as well, use 'icw search'. For the dates,
+authors and messages of the tags, use 'icw log'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runList(cmd, args)
 	},
@@ -405,23 +637,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	repoFlag, _ := cmd.Flags().GetString("repo")
 
 	// Determine which repository to use
-	var configRepo, configURL string
-
-	if repoFlag != "" {
-		// Use repository specified via --repo flag
-		configRepo = repoFlag
-	} else {
-		// Try to read workspace.config for repo configuration
-		root, err := config.FindWorkspaceRoot()
-		if err == nil {
-			ws := component.NewWorkspace(root)
-			parser := config.NewParser(ws)
-			if err := parser.ParseWorkspaceConfig(ws.Config); err == nil {
-				configRepo = parser.Repo
-				configURL = parser.SvnURL
-			}
-		}
-	}
+	configRepo, configURL := repoConfig(repoFlag)
 
 	// Create SVN client
 	svnClient, err := svn.NewClientWithConfig(configRepo, configURL)
@@ -433,7 +649,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		componentPath := args[0]
 		// Check if it contains a glob pattern
-		if strings.Contains(componentPath, "*") {
+		if svn.IsPattern(componentPath) {
 			return showMatchingComponents(svnClient, componentPath, showBranches, showTags, showAll)
 		}
 		return showComponentDetails(svnClient, componentPath, showBranches, showTags, showAll)
@@ -443,7 +659,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	// If so, show details instead of listing
 	if componentType != "" && strings.Contains(componentType, "/") {
 		// Check if it contains a glob pattern
-		if strings.Contains(componentType, "*") {
+		if svn.IsPattern(componentType) {
 			return showMatchingComponents(svnClient, componentType, showBranches, showTags, showAll)
 		}
 		return showComponentDetails(svnClient, componentType, showBranches, showTags, showAll)
@@ -473,18 +689,17 @@ func runList(cmd *cobra.Command, args []string) error {
 		color.Green("Total: %d components", len(components))
 	} else {
 		// List all components by type
-		types := []string{"analog", "digital", "setup", "process"}
 		totalCount := 0
 
-		for _, typ := range types {
-			components, err := svnClient.ListComponentsByType(typ)
+		for _, typ := range component.RepositoryTypes {
+			components, err := svnClient.ListComponentsByType(string(typ))
 			if err != nil {
 				color.Yellow("  [%s] Could not list: %v", typ, err)
 				continue
 			}
 
 			if len(components) > 0 {
-				color.Cyan("[%s]", strings.ToUpper(typ))
+				color.Cyan("[%s]", strings.ToUpper(string(typ)))
 				for _, comp := range components {
 					fmt.Printf("  %s\n", comp)
 				}
@@ -503,6 +718,15 @@ func showComponentDetails(svnClient *svn.Client, componentPath string, showBranc
 	color.Cyan("Component: %s", componentPath)
 	fmt.Println()
 
+	// Show metadata from trunk
+	if meta, err := fetchComponentInfo(svnClient, componentPath, "trunk"); err != nil {
+		color.Yellow("Invalid metadata: %v", err)
+		fmt.Println()
+	} else if !meta.IsEmpty() {
+		printComponentInfo("  ", meta)
+		fmt.Println()
+	}
+
 	// Get component info
 	info, err := svnClient.GetComponentInfo(componentPath)
 	if err != nil {
@@ -538,8 +762,13 @@ func showComponentDetails(svnClient *svn.Client, componentPath string, showBranc
 	if displayTags {
 		if len(info.Tags) > 0 {
 			color.Cyan("Tags (%d):", len(info.Tags))
+			latest := semver.Latest(info.Tags)
 			for _, tag := range info.Tags {
-				fmt.Printf("  %s\n", tag)
+				if tag == latest {
+					fmt.Printf("  %s %s\n", tag, color.GreenString("(latest)"))
+				} else {
+					fmt.Printf("  %s\n", tag)
+				}
 			}
 		} else {
 			color.Yellow("Tags: none")
@@ -555,7 +784,15 @@ func showMatchingComponents(svnClient *svn.Client, pattern string, showBranches,
 
 	// Find matching components
 	matches, err := svnClient.FindComponentsByPattern(pattern)
-	if err != nil {
+	var typesErr *svn.ListTypesError
+	if errors.As(err, &typesErr) {
+		// Not every repository has all component types
+		for _, typ := range component.RepositoryTypes {
+			if typeErr, ok := typesErr.Errs[string(typ)]; ok {
+				color.Yellow("  [%s] Could not list: %v", typ, typeErr)
+			}
+		}
+	} else if err != nil {
 		return fmt.Errorf("failed to find matching components: %w", err)
 	}
 
@@ -650,25 +887,97 @@ func runHdl() error {
 		return nil
 	}
 
-	// Load all dependencies
-	for _, comp := range ws.Components {
-		if comp.VCS == "local" {
-			continue
+	// Load all dependencies, including their HDL layout
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	var flow hdl.Flow
+	if flagHdlFlow != "" {
+		flow, err = hdl.ParseFlow(flagHdlFlow)
+		if err != nil {
+			return err
 		}
-		destPath := filepath.Join(root, comp.Path)
-		dependConfigPath := filepath.Join(destPath, "depend.config")
-		parser.ParseDependConfig(comp, dependConfigPath)
+	}
+
+	if flagHdlList && flagHdlManifest != "" {
+		return fmt.Errorf("--list and --manifest cannot be combined")
+	}
+	if flagHdlOptions && !flagHdlList {
+		return fmt.Errorf("--options requires --list")
+	}
+	if flagHdlList {
+		if flow == "" {
+			return fmt.Errorf("--list requires --flow")
+		}
+		return printHDLFileList(root, ws, flow, flagHdlOptions)
+	}
+
+	if flagHdlManifest != "" {
+		return writeHDLManifest(ws, parser, flow, flagHdlManifest)
 	}
 
 	// Print dependency tree with HDL files
-	color.Cyan("Dependency tree with HDL files\n")
+	if flow != "" {
+		color.Cyan("Dependency tree with HDL files for %s\n", flow)
+	} else {
+		color.Cyan("Dependency tree with HDL files\n")
+	}
 
 	// Track which components we've already printed to avoid duplicates
 	printed := make(map[string]bool)
 
 	// Print each top-level component and its dependencies
-	for _, comp := range ws.Components {
-		printComponentTreeWithHDL(comp, root, 0, printed)
+	for _, comp := range ws.Ordered() {
+		if comp.IsTopLevel() {
+			printComponentTreeWithHDL(comp, root, 0, printed, flow)
+		}
+	}
+
+	// Units defined twice clash at compile time
+	designs, _, err := workspaceDesigns(root, resolved)
+	if err != nil {
+		return err
+	}
+	checkFlow := flow
+	if checkFlow == "" {
+		checkFlow = hdl.FlowRTLSim
+	}
+	if duplicates := hdl.Duplicates(designs, checkFlow); len(duplicates) > 0 {
+		fmt.Println()
+		printDuplicates(root, duplicates)
+		if flagHdlStrict {
+			return fmt.Errorf("%d duplicate design unit(s)", len(duplicates))
+		}
+	}
+
+	return nil
+}
+
+// printHDLFileList prints the sources of a flow for all digital components in
+// compile order, one path per line. Headers are left out, as they are only
+// reached through include directories. With options the sources are preceded
+// by the +incdir+ and +define+ lines, as in the filelist of icw export.
+func printHDLFileList(root string, ws *component.Workspace, flow hdl.Flow, options bool) error {
+	discover := func(comp *component.Component) (*hdl.HDLFiles, error) {
+		return discoverComponentHDL(root, comp)
+	}
+	project, err := buildProject(root, filepath.Base(root), ws.Ordered(), flow, "", discover)
+	if err != nil {
+		return err
+	}
+
+	if options {
+		for _, dir := range project.Incdirs() {
+			fmt.Printf("+incdir+%s\n", dir)
+		}
+		for _, def := range project.Defines() {
+			fmt.Printf("+define+%s\n", def)
+		}
+	}
+	for _, file := range project.Sources() {
+		fmt.Println(file)
 	}
 
 	return nil
@@ -705,6 +1014,15 @@ func printComponentTreeFromConfigs(comp *component.Component, workspaceRoot stri
 		dependConfigContent, err = svnClient.Cat(comp.Path, comp.Branch, "depend.config")
 	}
 
+	// Print metadata below the component
+	if flagTreeLong {
+		info, infoErr := treeComponentInfo(svnClient, comp, componentPath, dependConfigContent)
+		if infoErr != nil {
+			color.Yellow("%s  - could not read metadata: %v", indentStr, infoErr)
+		}
+		printComponentInfo(indentStr+"  - ", info)
+	}
+
 	if err != nil {
 		// No depend.config or error reading it - that's OK, component has no dependencies
 		return
@@ -781,7 +1099,7 @@ func parseComponentLineSimple(line string) *component.Component {
 	return nil
 }
 
-func printComponentTreeWithHDL(comp *component.Component, workspaceRoot string, indent int, printed map[string]bool) {
+func printComponentTreeWithHDL(comp *component.Component, workspaceRoot string, indent int, printed map[string]bool, flow hdl.Flow) {
 	// Skip if already printed
 	if printed[comp.Name] {
 		return
@@ -794,9 +1112,14 @@ func printComponentTreeWithHDL(comp *component.Component, workspaceRoot string,
 
 	// Only discover HDL files for digital components
 	if comp.Type == component.TypeDigital && comp.VCS != "local" {
-		componentPath := filepath.Join(workspaceRoot, comp.Path)
-		hdlFiles, err := hdl.DiscoverFiles(componentPath)
-		if err == nil {
+		hdlFiles, err := discoverComponentHDL(workspaceRoot, comp)
+		if err == nil && flow != "" {
+			// Print only the files used by the selected flow
+			if files := hdlFiles.ForFlow(flow); len(files) > 0 {
+				fileList := shortenPaths(files, workspaceRoot)
+				fmt.Printf("%s  - %s: %s\n", indentStr, flow, strings.Join(fileList, " "))
+			}
+		} else if err == nil {
 			// Print package files
 			if len(hdlFiles.Package) > 0 {
 				fileList := shortenPaths(hdlFiles.Package, workspaceRoot)
@@ -814,13 +1137,87 @@ func printComponentTreeWithHDL(comp *component.Component, workspaceRoot string,
 				fileList := shortenPaths(hdlFiles.Behav, workspaceRoot)
 				fmt.Printf("%s  - behav: %s\n", indentStr, strings.Join(fileList, " "))
 			}
+
+			// Print gate-level netlists, models and IP
+			if len(hdlFiles.Gate) > 0 {
+				fileList := shortenPaths(hdlFiles.Gate, workspaceRoot)
+				fmt.Printf("%s  - gate: %s\n", indentStr, strings.Join(fileList, " "))
+			}
+			if len(hdlFiles.Model) > 0 {
+				fileList := shortenPaths(hdlFiles.Model, workspaceRoot)
+				fmt.Printf("%s  - model: %s\n", indentStr, strings.Join(fileList, " "))
+			}
+			if len(hdlFiles.IP) > 0 {
+				fileList := shortenPaths(hdlFiles.IP, workspaceRoot)
+				fmt.Printf("%s  - ip: %s\n", indentStr, strings.Join(fileList, " "))
+			}
+		}
+
+		// Print compile settings, including those inherited from dependencies
+		if incdirs := componentIncdirs(workspaceRoot, comp); len(incdirs) > 0 {
+			fileList := shortenPaths(incdirs, workspaceRoot)
+			fmt.Printf("%s  - incdir: %s\n", indentStr, strings.Join(fileList, " "))
+		}
+		if defines := component.InheritedDefines(comp); len(defines) > 0 {
+			var names []string
+			for _, def := range defines {
+				names = append(names, def.String())
+			}
+			fmt.Printf("%s  - define: %s\n", indentStr, strings.Join(names, " "))
+		}
+		if comp.Library != "" {
+			fmt.Printf("%s  - library: %s\n", indentStr, comp.Library)
+		}
+		if comp.Top != "" {
+			fmt.Printf("%s  - top: %s\n", indentStr, comp.Top)
 		}
 	}
 
 	// Recursively print dependencies
 	for _, dep := range comp.Dependencies {
-		printComponentTreeWithHDL(dep, workspaceRoot, indent+2, printed)
+		printComponentTreeWithHDL(dep, workspaceRoot, indent+2, printed, flow)
+	}
+}
+
+// hdlLayout returns the HDL source layout declared in a component's depend.config
+func hdlLayout(comp *component.Component) hdl.Layout {
+	layout := hdl.Layout{Dirs: comp.HDLDirs, Exclude: comp.HDLExclude}
+	for _, rule := range comp.FlowRules {
+		layout.Rules = append(layout.Rules, hdl.FlowRule{
+			Include: rule.Include,
+			Flow:    hdl.Flow(rule.Flow),
+			Pattern: rule.Pattern,
+		})
 	}
+	return layout
+}
+
+// discoverComponentHDL finds the HDL files of a component using its declared layout
+func discoverComponentHDL(root string, comp *component.Component) (*hdl.HDLFiles, error) {
+	return hdl.DiscoverFilesWithLayout(filepath.Join(root, comp.Path), hdlLayout(comp))
+}
+
+// componentIncdirs returns the absolute include directories of a component
+// and all of its dependencies
+func componentIncdirs(root string, comp *component.Component) []string {
+	var dirs []string
+	for _, dir := range component.InheritedIncdirs(comp) {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, filepath.FromSlash(dir))
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// appendIncdirs appends the directories not yet in dirs
+func appendIncdirs(dirs []string, add ...string) []string {
+	for _, dir := range add {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
 }
 
 func shortenPaths(paths []string, workspaceRoot string) []string {
@@ -860,11 +1257,21 @@ func runStatus() error {
 		return fmt.Errorf("failed to create SVN client: %w", err)
 	}
 
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	if flagStatusInteractive {
+		return runInteractiveStatus(root, resolved, svnClient)
+	}
+
 	color.Cyan("Workspace status:\n")
+	printProcessStatus(root, resolved)
 
-	// Check each component
+	// Check each component in the resolved tree
 	hasChanges := false
-	for _, comp := range ws.Components {
+	for _, comp := range resolved {
 		if comp.VCS == "local" {
 			continue
 		}
@@ -873,7 +1280,11 @@ func runStatus() error {
 
 		// Check if component is checked out
 		if !svn.IsWorkingCopy(destPath) {
-			color.Yellow("[NOT CHECKED OUT] %s", comp.Name)
+			if comp.IsTopLevel() {
+				color.Yellow("[NOT CHECKED OUT] %s", comp.Name)
+			} else {
+				color.Red("[MISSING DEPENDENCY] %s (%s, required by %s)", comp.Name, comp.Branch, comp.DeclaredBy)
+			}
 			hasChanges = true
 			continue
 		}
@@ -885,17 +1296,51 @@ func runStatus() error {
 			continue
 		}
 
-		// Check if there are any changes
+		// Collect state labels for the component
+		var labels []string
+		if strings.TrimSpace(status) != "" {
+			labels = append(labels, "[MODIFIED]")
+		}
+
+		// Check that the working copy is on the declared branch. If that
+		// fails, local modifications are still reported.
+		branch, err := svnClient.GetBranch(destPath)
+		if err != nil {
+			color.Red("[ERROR] %s: %v", comp.Name, err)
+			branch = comp.Branch
+		}
+		if branch != comp.Branch {
+			labels = append(labels, "[WRONG BRANCH]")
+		}
+
+		// Compare with the repository unless working offline
+		if !flagStatusOffline {
+			outdated, err := svnClient.OutdatedRevisions(destPath)
+			if err != nil {
+				color.Red("[ERROR] %s: %v", comp.Name, err)
+			} else if outdated > 0 {
+				labels = append(labels, fmt.Sprintf("[OUTDATED %d revs]", outdated))
+			}
+		}
+
+		if len(labels) == 0 {
+			color.Green("[CLEAN] %s (%s)", comp.Name, comp.Branch)
+			continue
+		}
+
+		hasChanges = true
+		if branch != comp.Branch {
+			color.Yellow("%s %s (checked out: %s, declared: %s)", strings.Join(labels, " "), comp.Name, branch, comp.Branch)
+		} else {
+			color.Yellow("%s %s (%s)", strings.Join(labels, " "), comp.Name, comp.Branch)
+		}
+
+		// Print the local changes with indentation
 		if strings.TrimSpace(status) != "" {
-			color.Yellow("[MODIFIED] %s (%s)", comp.Name, comp.Branch)
-			// Print the status with indentation
 			lines := strings.Split(strings.TrimSpace(status), "\n")
 			for _, line := range lines {
 				fmt.Printf("  %s\n", line)
 			}
-			hasChanges = true
-		} else {
-			color.Green("[CLEAN] %s (%s)", comp.Name, comp.Branch)
 		}
 	}
 
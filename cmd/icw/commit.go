@@ -0,0 +1,198 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var commitCmd = &cobra.Command{
+	Use:     "commit [component...]",
+	Aliases: []string{"ci"},
+	Short:   "Commit changes across multiple components",
+	Long: `Commit local changes in one or more components with a shared message.
+
+Each component is a separate working copy and gets its own SVN commit.
+Components are committed with dependencies before the components that use
+them. Every log message carries the same change-set id so a cross-component
+change can be found again later:
+
+  <message>
+
+  Change-set: 20261018T153012-jkj-4f2a91c0
+
+Unversioned files are not committed; use 'icw status --interactive' to add them.
+
+Examples:
+  icw commit -m "Fix reset polarity"                  # All modified components
+  icw commit -m "New SPI timing" digital/spi digital/top`,
+	RunE: runCommit,
+}
+
+// Command flags
+var (
+	flagCommitMessage string
+	flagCommitYes     bool
+)
+
+func init() {
+	rootCmd.AddCommand(commitCmd)
+
+	commitCmd.Flags().StringVarP(&flagCommitMessage, "message", "m", "", "Commit message")
+	commitCmd.Flags().BoolVarP(&flagCommitYes, "yes", "y", false, "Do not ask for confirmation")
+	commitCmd.MarkFlagRequired("message")
+}
+
+// componentChanges holds the committable changes of one component
+type componentChanges struct {
+	comp    *component.Component
+	path    string
+	entries []svn.StatusEntry
+}
+
+func runCommit(cmd *cobra.Command, args []string) error {
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	if _, err := parser.ResolveLocal(); err != nil {
+		return err
+	}
+
+	// Commit dependencies before the components that use them
+	ordered := component.DependencyOrder(ws.Ordered())
+	selected, err := selectComponents(ordered, args)
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		selected = filterInOrder(ordered, selected)
+	}
+
+	svnClient, err := newSVNClient(parser)
+	if err != nil {
+		return err
+	}
+
+	// Gather modified working copies
+	var changes []componentChanges
+	for _, comp := range selected {
+		if comp.VCS != "svn" {
+			continue
+		}
+
+		destPath := filepath.Join(ws.Root, comp.Path)
+		if !svn.IsWorkingCopy(destPath) {
+			continue
+		}
+
+		status, err := svnClient.Status(destPath)
+		if err != nil {
+			return fmt.Errorf("failed to get status of %s: %w", comp.Name, err)
+		}
+
+		var modified []svn.StatusEntry
+		for _, entry := range svn.ParseStatus(status) {
+			if entry.Code == 'C' {
+				return fmt.Errorf("%s has unresolved conflicts: %s", comp.Name, relPath(ws.Root, entry.Path))
+			}
+			if entry.IsModified() {
+				modified = append(modified, entry)
+			}
+		}
+
+		if len(modified) > 0 {
+			changes = append(changes, componentChanges{comp: comp, path: destPath, entries: modified})
+		}
+	}
+
+	if len(changes) == 0 {
+		color.Green("Nothing to commit - no modified components")
+		return nil
+	}
+
+	// Show combined change summary
+	changeSet, err := newChangeSetID()
+	if err != nil {
+		return err
+	}
+	color.Cyan("Change-set %s\n", changeSet)
+	total := 0
+	for _, ch := range changes {
+		color.Yellow("%s (%s)", ch.comp.Name, ch.comp.Branch)
+		for _, entry := range ch.entries {
+			fmt.Printf("  %c %s\n", entry.Code, relPath(ch.path, entry.Path))
+		}
+		total += len(ch.entries)
+	}
+	fmt.Println()
+	color.Cyan("%d change(s) in %d component(s)", total, len(changes))
+
+	if !flagCommitYes {
+		if !askYesNo("Commit?", false) {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	message := fmt.Sprintf("%s\n\nChange-set: %s", flagCommitMessage, changeSet)
+
+	committed := 0
+	for _, ch := range changes {
+		color.Yellow("  [COMMIT] %s", ch.comp.Name)
+		revision, err := svnClient.Commit(ch.path, message)
+		if err != nil {
+			color.Red("    Failed: %v", err)
+			if committed > 0 {
+				color.Red("    %d component(s) already committed with change-set %s", committed, changeSet)
+			}
+			return fmt.Errorf("commit of %s failed", ch.comp.Name)
+		}
+		if revision != "" {
+			color.Green("    Committed revision %s", revision)
+		}
+		committed++
+	}
+
+	color.Green("\nCommitted %d component(s) with change-set %s", committed, changeSet)
+	return nil
+}
+
+// filterInOrder returns the components of subset in the order they appear in ordered
+func filterInOrder(ordered, subset []*component.Component) []*component.Component {
+	wanted := make(map[string]bool)
+	for _, comp := range subset {
+		wanted[comp.Name] = true
+	}
+
+	var result []*component.Component
+	for _, comp := range ordered {
+		if wanted[comp.Name] {
+			result = append(result, comp)
+		}
+	}
+	return result
+}
+
+// newChangeSetID returns an id shared by all commits of one icw commit run
+func newChangeSetID() (string, error) {
+	user := os.Getenv("USER")
+	if user == "" {
+		user = "anonymous"
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to create change-set id: %w", err)
+	}
+
+	return fmt.Sprintf("%s-%s-%s", time.Now().Format("20060102T150405"), user, hex.EncodeToString(suffix)), nil
+}
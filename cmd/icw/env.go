@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/shellenv"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print shell commands that set up the workspace environment",
+	Long: `Print environment variables for the workspace as shell commands.
+
+The following variables are set:
+  ICW_ROOT        Workspace root
+  ICW_REPO        Repository name from workspace.config
+  ICW_PROCESS     Path of the process component
+  <TYPE>_<NAME>   Path of each component, e.g. DIGITAL_TOP for digital/top
+
+Components can export their own variables with env directives in
+depend.config. Values may refer to $ICW_ROOT, $ICW_COMPONENT_PATH (the
+component's own directory) and any variable set before:
+
+  env PDK_ROOT "$ICW_COMPONENT_PATH/pdk"
+
+The shell defaults to the one in $SHELL.
+
+Examples:
+  eval $(icw env)                    # bash/zsh
+  eval ` + "`icw env --shell csh`" + `        # csh/tcsh
+  icw env --shell fish | source      # fish
+  icw env --shell json               # For scripts`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runEnv()
+	},
+}
+
+// Command flags
+var flagEnvShell string
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().StringVar(&flagEnvShell, "shell", "", "Output format: bash, zsh, csh, fish or json")
+}
+
+func runEnv() error {
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	shell := flagEnvShell
+	if shell == "" {
+		shell = defaultShell()
+	}
+
+	vars, err := workspaceEnv(ws.Root, parser.Repo, resolved)
+	if err != nil {
+		return err
+	}
+
+	out, err := shellenv.Format(shell, vars)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
+
+// workspaceEnv derives the environment variables of a workspace from its
+// resolved components
+func workspaceEnv(root, repo string, comps []*component.Component) ([]shellenv.Var, error) {
+	var vars []shellenv.Var
+	index := make(map[string]int)
+
+	set := func(name, value, source string) {
+		if i, ok := index[name]; ok {
+			if vars[i].Value != value {
+				fmt.Fprintf(os.Stderr, "icw: %s from %s overrides %q\n", name, source, vars[i].Value)
+			}
+			vars[i].Value = value
+			return
+		}
+		index[name] = len(vars)
+		vars = append(vars, shellenv.Var{Name: name, Value: value})
+	}
+
+	set("ICW_ROOT", root, "workspace")
+	if repo != "" {
+		set("ICW_REPO", repo, "workspace.config")
+	}
+
+	proc, err := processComponent(comps)
+	if err != nil {
+		return nil, err
+	}
+	if proc != nil {
+		set("ICW_PROCESS", componentDir(root, proc), proc.Name)
+	}
+
+	for _, comp := range comps {
+		set(shellenv.Name(comp.Name), componentDir(root, comp), comp.Name)
+	}
+
+	// Component exports last, so they can refer to the paths above
+	for _, comp := range comps {
+		compDir := componentDir(root, comp)
+		lookup := func(name string) (string, bool) {
+			if name == "ICW_COMPONENT_PATH" {
+				return compDir, true
+			}
+			if i, ok := index[name]; ok {
+				return vars[i].Value, true
+			}
+			return os.LookupEnv(name)
+		}
+
+		for _, v := range comp.Env {
+			set(v.Name, shellenv.Expand(v.Value, lookup), comp.Name)
+		}
+	}
+
+	return vars, nil
+}
+
+// componentDir returns the absolute directory of a component.
+// Local references may be given with an absolute path.
+func componentDir(root string, comp *component.Component) string {
+	if filepath.IsAbs(comp.Path) {
+		return comp.Path
+	}
+	return filepath.Join(root, comp.Path)
+}
+
+// defaultShell returns the output format matching the user's login shell
+func defaultShell() string {
+	switch shell := filepath.Base(os.Getenv("SHELL")); shell {
+	case "zsh", "csh", "fish":
+		return shell
+	case "tcsh":
+		return "csh"
+	}
+	return "bash"
+}
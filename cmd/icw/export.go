@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/export"
+	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [component...]",
+	Short: "Generate simulator and synthesis project files",
+	Long: `Generate tool project files for digital components and their dependencies.
+
+Targets:
+  filelist    <name>.f with +incdir+/+define+ and sources in compile order
+  verilator   <name>.verilator.f command file with --top-module
+  yosys       <name>.ys script with read_verilog and hierarchy
+  openroad    <name>.mk with DESIGN_NAME and VERILOG_* for OpenROAD Flow Scripts
+  fusesoc     One CAPI2 .core file per component with its dependencies
+  edalize     <name>.edam.json description for edalize
+  make        <name>.hdl.mk make fragment with HDL_FILES, HDL_INCDIRS, HDL_DEFINES
+  tcl         <name>.tcl script setting the same variables as Tcl lists
+
+Without arguments all digital components in workspace.config are exported.
+Files are selected for --flow, which defaults to synthesis for yosys and
+openroad and to rtl-sim otherwise. The incdir, define and library statements
+of depend.config (see 'icw hdl --help') are exported together with the
+directories holding header files. The top-level module is taken from --top,
+from the top statement of a single exported component, or detected when the
+design has exactly one.
+
+Examples:
+  icw export -t filelist                       # build/<workspace>.f
+  icw export -t verilator --top top_tb         # Simulate a testbench
+  icw export -t yosys digital/top -o syn       # Synthesis script for one component
+  icw export -t fusesoc -o cores`,
+	RunE: runExport,
+}
+
+// Command flags
+var (
+	flagExportTarget string
+	flagExportFlow   string
+	flagExportOutput string
+	flagExportTop    string
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&flagExportTarget, "target", "t", "", "Export target: filelist, verilator, yosys, openroad, fusesoc, edalize, make or tcl")
+	exportCmd.Flags().StringVar(&flagExportFlow, "flow", "", "Flow to select files for (synthesis, rtl-sim, gate-sim)")
+	exportCmd.Flags().StringVarP(&flagExportOutput, "output", "o", "build", "Output directory")
+	exportCmd.Flags().StringVar(&flagExportTop, "top", "", "Top-level module")
+	exportCmd.MarkFlagRequired("target")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	flow := defaultExportFlow(flagExportTarget)
+	if flagExportFlow != "" {
+		var err error
+		flow, err = hdl.ParseFlow(flagExportFlow)
+		if err != nil {
+			return err
+		}
+	}
+
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	var roots []*component.Component
+	if len(args) > 0 {
+		roots, err = selectComponents(resolved, args)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, comp := range ws.Ordered() {
+			if comp.IsTopLevel() && comp.Type == component.TypeDigital {
+				roots = append(roots, comp)
+			}
+		}
+	}
+
+	name := filepath.Base(ws.Root)
+	if len(roots) == 1 {
+		name = filepath.Base(roots[0].Name)
+	}
+
+	discover := func(comp *component.Component) (*hdl.HDLFiles, error) {
+		return discoverComponentHDL(ws.Root, comp)
+	}
+	project, err := buildProject(ws.Root, name, roots, flow, flagExportTop, discover)
+	if err != nil {
+		return err
+	}
+	if len(project.Components) == 0 {
+		color.Yellow("No digital components to export")
+		return nil
+	}
+
+	outDir := flagExportOutput
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(ws.Root, outDir)
+	}
+
+	written, err := export.Generate(flagExportTarget, project, outDir)
+	if err != nil {
+		return err
+	}
+
+	if project.Top == "" && flagExportTarget != "filelist" && flagExportTarget != "fusesoc" {
+		color.Yellow("No unique top-level module found; use --top to set it")
+	}
+	for _, path := range written {
+		color.Green("  [EXPORT] %s", relPath(ws.Root, path))
+	}
+	return nil
+}
+
+// defaultExportFlow returns the flow a target selects files for by default
+func defaultExportFlow(target string) hdl.Flow {
+	if target == "yosys" || target == "openroad" {
+		return hdl.FlowSynthesis
+	}
+	return hdl.FlowRTLSim
+}
+
+// buildProject collects the HDL files of roots and their dependencies for a
+// flow, using discover to find the files of each component. An empty top is
+// taken from depend.config or detected.
+func buildProject(root, name string, roots []*component.Component, flow hdl.Flow, top string, discover func(*component.Component) (*hdl.HDLFiles, error)) (*export.Project, error) {
+	project := &export.Project{Name: name, Flow: string(flow), Top: top}
+
+	var sets []*hdl.HDLFiles
+	var designs []hdl.Design
+	for _, comp := range component.DependencyOrder(roots) {
+		if !isHDLComponent(comp) {
+			continue
+		}
+
+		files, err := discover(comp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover HDL files of %s: %w", comp.Name, err)
+		}
+		sets = append(sets, files)
+		designs = append(designs, hdl.Design{Component: comp.Name, Files: files})
+
+		// Include directories and defines are inherited from dependencies,
+		// so each component carries everything it needs to compile
+		flowFiles := files.ForFlow(flow)
+		exported := export.Component{
+			Name:    comp.Name,
+			Type:    string(comp.Type),
+			Branch:  comp.Branch,
+			Library: comp.Library,
+			Files:   flowFiles,
+			Incdirs: appendIncdirs(componentIncdirs(root, comp), export.HeaderDirs(flowFiles)...),
+			Defines: component.InheritedDefines(comp),
+			Own:     comp.Defines,
+		}
+		for _, dep := range comp.Dependencies {
+			if isHDLComponent(dep) {
+				exported.Deps = append(exported.Deps, dep.Name)
+			}
+		}
+		project.Components = append(project.Components, exported)
+	}
+
+	project.Files = hdl.FlowFiles(sets, flow)
+
+	// A single root exports the top-level unit declared in its depend.config
+	if project.Top == "" && len(roots) == 1 {
+		project.Top = roots[0].Top
+	}
+	if project.Top == "" {
+		if tops := hdl.Check(designs, flow).Tops; len(tops) == 1 {
+			project.Top = tops[0].Name
+		}
+	}
+
+	return project, nil
+}
+
+// isHDLComponent reports whether a component has HDL files to export
+func isHDLComponent(comp *component.Component) bool {
+	return comp.Type == component.TypeDigital && comp.VCS != "local"
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/spf13/cobra"
+)
+
+var hdlCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Find top-level modules and instances of undeclared components",
+	Long: `Build the instantiation graph of all digital components in the workspace,
+list the top-level modules and entities, and report instances and package
+imports whose definition is not found in the component or its declared
+dependencies.
+
+If another component in the workspace defines the missing unit, it is shown
+as the dependency to add to depend.config. Working copies under digital/
+that are not part of the dependency tree are searched as well.
+
+The command fails if unresolved references are found.
+
+Examples:
+  icw hdl check                        # Check the RTL simulation file set
+  icw hdl check --flow synthesis       # Check the synthesis file set`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHdlCheck()
+	},
+}
+
+// Command flags
+var flagHdlCheckFlow string
+
+func init() {
+	hdlCmd.AddCommand(hdlCheckCmd)
+
+	hdlCheckCmd.Flags().StringVar(&flagHdlCheckFlow, "flow", string(hdl.FlowRTLSim), "Flow whose files are checked (synthesis, rtl-sim, gate-sim)")
+}
+
+func runHdlCheck() error {
+	flow, err := hdl.ParseFlow(flagHdlCheckFlow)
+	if err != nil {
+		return err
+	}
+
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	designs, byName, err := workspaceDesigns(ws.Root, resolved)
+	if err != nil {
+		return err
+	}
+	if len(designs) == 0 {
+		color.Yellow("No digital components in the workspace")
+		return nil
+	}
+
+	report := hdl.Check(designs, flow)
+
+	color.Cyan("Top-level units (%s):", flow)
+	if len(report.Tops) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, unit := range report.Tops {
+		fmt.Printf("  %s (%s) %s\n", unit.Name, unit.Kind, relPath(ws.Root, unit.File))
+	}
+	fmt.Println()
+
+	if len(report.Unresolved) == 0 {
+		color.Green("All instances resolved")
+		return nil
+	}
+
+	color.Cyan("Unresolved references (%d):", len(report.Unresolved))
+	for _, ref := range report.Unresolved {
+		color.Red("  [UNRESOLVED] %s in %s", ref.Unit, relPath(ws.Root, ref.File))
+
+		if len(ref.DefinedIn) == 0 {
+			fmt.Println("    Not defined in any component in the workspace")
+			continue
+		}
+		for _, owner := range ref.DefinedIn {
+			fmt.Printf("    Defined in %s; add to %s/depend.config:\n", owner, ref.Component)
+			fmt.Printf("      %s\n", useDirective(owner, byName[owner]))
+		}
+	}
+
+	return fmt.Errorf("%d unresolved reference(s)", len(report.Unresolved))
+}
+
+// workspaceDesigns discovers the HDL files of all resolved digital components
+// and of undeclared digital working copies, which are only used as candidates
+// for missing dependencies
+func workspaceDesigns(root string, resolved []*component.Component) ([]hdl.Design, map[string]*component.Component, error) {
+	var designs []hdl.Design
+	byName := make(map[string]*component.Component)
+	declared := make(map[string]bool)
+
+	for _, comp := range component.DependencyOrder(resolved) {
+		declared[filepath.Clean(comp.Path)] = true
+		if comp.Type != component.TypeDigital || comp.VCS == "local" {
+			continue
+		}
+
+		files, err := discoverComponentHDL(root, comp)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to discover HDL files of %s: %w", comp.Name, err)
+		}
+
+		var deps []string
+		for _, dep := range component.DependencyOrder([]*component.Component{comp}) {
+			if dep.Name != comp.Name {
+				deps = append(deps, dep.Name)
+			}
+		}
+
+		designs = append(designs, hdl.Design{Component: comp.Name, Deps: deps, Files: files})
+		byName[comp.Name] = comp
+	}
+
+	workingCopies, err := findWorkingCopies(root, []string{"digital"})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, wc := range workingCopies {
+		if declared[wc] {
+			continue
+		}
+
+		files, err := hdl.DiscoverFiles(filepath.Join(root, wc))
+		if err != nil {
+			continue
+		}
+		designs = append(designs, hdl.Design{Component: wc, Files: files, Candidate: true})
+	}
+
+	return designs, byName, nil
+}
+
+// useDirective returns the depend.config line declaring a dependency on a component
+func useDirective(name string, comp *component.Component) string {
+	if comp == nil {
+		return fmt.Sprintf("use component(\"%s\", \"digital\")", name)
+	}
+	return fmt.Sprintf("use component(\"%s\", \"%s\", \"%s\")", comp.Path, comp.Type, comp.Branch)
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var hdlDiffCmd = &cobra.Command{
+	Use:   "diff <old-manifest>",
+	Short: "List components and files changed since a manifest",
+	Long: `Compare the HDL files of the workspace with a manifest written by
+'icw hdl --manifest' and list the components that need to be recompiled.
+
+  [MODIFIED]  Files of the component were added, removed or changed
+  [ADDED]     The component is new in the workspace
+  [REMOVED]   The component is no longer part of the workspace
+  [AFFECTED]  The component is unchanged, but one of its dependencies changed
+
+The files are selected for the flow the manifest was written for. A new SVN
+revision without changes to the HDL files is not a change. With --json the
+changes are printed as JSON for build scripts.
+
+Examples:
+  icw hdl diff build/hdl.json
+  icw hdl diff build/hdl.json --json | jq -r '.[].name'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHdlDiff(args[0])
+	},
+}
+
+// Command flags
+var (
+	flagHdlDiffJSON bool
+)
+
+func init() {
+	hdlCmd.AddCommand(hdlDiffCmd)
+
+	hdlDiffCmd.Flags().BoolVar(&flagHdlDiffJSON, "json", false, "Print the changes as JSON")
+}
+
+// writeHDLManifest writes the manifest of the workspace to path, or to stdout for "-"
+func writeHDLManifest(ws *component.Workspace, parser *config.Parser, flow hdl.Flow, path string) error {
+	manifest, err := buildManifest(ws, parser, flow)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		return hdl.WriteManifest(os.Stdout, manifest)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ws.Root, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	defer file.Close()
+
+	if err := hdl.WriteManifest(file, manifest); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	color.Green("  [MANIFEST] %s (%d components)", relPath(ws.Root, path), len(manifest.Components))
+	return nil
+}
+
+// buildManifest hashes the HDL files of all digital components, dependencies first
+func buildManifest(ws *component.Workspace, parser *config.Parser, flow hdl.Flow) (*hdl.Manifest, error) {
+	// Revisions are informational, so a missing SVN setup is not an error
+	svnClient, err := newSVNClient(parser)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "icw: revisions not recorded: %v\n", err)
+	}
+
+	manifest := &hdl.Manifest{Flow: flow}
+	for _, comp := range component.DependencyOrder(ws.Ordered()) {
+		if !isHDLComponent(comp) {
+			continue
+		}
+
+		files, err := discoverComponentHDL(ws.Root, comp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover HDL files of %s: %w", comp.Name, err)
+		}
+		entry, err := hdl.NewManifestComponent(comp.Name, files, flow, componentIncdirs(ws.Root, comp))
+		if err != nil {
+			return nil, err
+		}
+
+		entry.Branch = comp.Branch
+		for _, dep := range comp.Dependencies {
+			if isHDLComponent(dep) {
+				entry.Deps = append(entry.Deps, dep.Name)
+			}
+		}
+		if dir := filepath.Join(ws.Root, comp.Path); svnClient != nil && svn.IsWorkingCopy(dir) {
+			if revision, err := svnClient.Revision(dir); err == nil {
+				entry.Revision = revision
+			}
+		}
+
+		manifest.Components = append(manifest.Components, entry)
+	}
+
+	return manifest, nil
+}
+
+func runHdlDiff(oldPath string) error {
+	old, err := hdl.ReadManifest(oldPath)
+	if err != nil {
+		return err
+	}
+
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	if _, err := parser.ResolveLocal(); err != nil {
+		return err
+	}
+
+	current, err := buildManifest(ws, parser, old.Flow)
+	if err != nil {
+		return err
+	}
+
+	changes := hdl.DiffManifests(old, current)
+
+	if flagHdlDiffJSON {
+		if changes == nil {
+			changes = []hdl.ComponentChange{}
+		}
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(changes) == 0 {
+		color.Green("No HDL changes since %s", oldPath)
+		return nil
+	}
+
+	for _, change := range changes {
+		switch change.Status {
+		case hdl.Modified:
+			color.Yellow("  [MODIFIED] %s%s", change.Name, revisionRange(change))
+		case hdl.Added:
+			color.Green("  [ADDED] %s", change.Name)
+		case hdl.Removed:
+			color.Red("  [REMOVED] %s", change.Name)
+		case hdl.Affected:
+			color.Cyan("  [AFFECTED] %s", change.Name)
+			for _, dep := range change.ChangedDeps {
+				fmt.Printf("      depends on %s\n", dep)
+			}
+		}
+		for _, file := range change.Files {
+			fmt.Printf("      %s %s\n", fileStatusLetter(file.Status), file.Path)
+		}
+	}
+
+	return nil
+}
+
+// revisionRange formats the revision change of a component, if known
+func revisionRange(change hdl.ComponentChange) string {
+	if change.OldRevision == 0 || change.NewRevision == 0 || change.OldRevision == change.NewRevision {
+		return ""
+	}
+	return fmt.Sprintf(" (r%d -> r%d)", change.OldRevision, change.NewRevision)
+}
+
+// fileStatusLetter returns the svn status style letter of a file change
+func fileStatusLetter(status hdl.ChangeStatus) string {
+	switch status {
+	case hdl.Added:
+		return "A"
+	case hdl.Removed:
+		return "D"
+	}
+	return "M"
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/hook"
+)
+
+// componentHooks returns the hooks to run for a component after an update:
+// type-wide hooks from workspace.config first, then the component's own.
+// fresh is true when the component was checked out for the first time.
+func componentHooks(parser *config.Parser, root string, comp *component.Component, fresh bool) []component.Hook {
+	var all []component.Hook
+	all = append(all, parser.TypeHooks[comp.Type]...)
+	all = append(all, comp.Hooks...)
+
+	// Setup components run their setup script like the legacy tool did,
+	// unless the component declares its own hooks
+	if comp.Type == component.TypeSetup && len(comp.Hooks) == 0 {
+		script := filepath.Join(root, comp.Path, "setup")
+		if info, err := os.Stat(script); err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+			all = append(all, component.Hook{Event: component.HookPostUpdate, Command: "./setup", Source: comp.Name})
+		}
+	}
+
+	var hooks []component.Hook
+	for _, h := range all {
+		if h.Event == component.HookPostUpdate || (fresh && h.Event == component.HookPostCheckout) {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+// runHooks runs the post-update hooks of all updated components in order.
+// Hooks from components that are not trusted yet need confirmation.
+func runHooks(parser *config.Parser, root string, updated []*component.Component, fresh map[string]bool) int {
+	failed := 0
+
+	for _, comp := range updated {
+		hooks := componentHooks(parser, root, comp, fresh[comp.Name])
+		dir := filepath.Join(root, comp.Path)
+		for _, h := range hooks {
+			if !hook.IsTrusted(h, dir) && !confirmHook(h, dir) {
+				color.Yellow("  [HOOK] %s: skipped %s", comp.Name, h.Command)
+				continue
+			}
+
+			color.Cyan("  [HOOK] %s: %s", comp.Name, h.Command)
+			if err := hook.Run(h, dir, hook.Environment(root, comp), flagUpdateHookTimeout); err != nil {
+				color.Red("    Failed: %v", err)
+				failed++
+			}
+		}
+	}
+
+	return failed
+}
+
+// confirmHook asks whether an untrusted hook may run in dir
+func confirmHook(h component.Hook, dir string) bool {
+	color.Yellow("  %s wants to run a %s hook:", h.Source, h.Event)
+	fmt.Printf("    %s\n", h.Command)
+
+	switch askChoice("  Run it? [n]o, [y]es once, [a]lways trust this hook:", "nya") {
+	case 'y':
+		return true
+	case 'a':
+		if err := hook.Trust(h, dir); err != nil {
+			color.Red("    Failed to store trust: %v", err)
+		}
+		return true
+	}
+	return false
+}
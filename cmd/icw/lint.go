@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check the workspace HDL for problems",
+	Long: `Check the HDL files of all digital components in the workspace.
+
+Checks:
+  - Duplicate design units: a module, entity, package or interface defined
+    in more than one file, e.g. two components each with their own sync_ff.
+    Simulators and synthesis tools silently pick one of them.
+
+Problems are reported as warnings. Use --strict to fail the command, e.g. in CI.
+
+Examples:
+  icw lint                             # Check the RTL simulation file set
+  icw lint --flow synthesis --strict   # Fail if synthesis files clash`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLint()
+	},
+}
+
+// Command flags
+var (
+	flagLintFlow   string
+	flagLintStrict bool
+)
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVar(&flagLintFlow, "flow", string(hdl.FlowRTLSim), "Flow whose files are checked (synthesis, rtl-sim, gate-sim)")
+	lintCmd.Flags().BoolVar(&flagLintStrict, "strict", false, "Exit with an error if problems are found")
+}
+
+func runLint() error {
+	flow, err := hdl.ParseFlow(flagLintFlow)
+	if err != nil {
+		return err
+	}
+
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	designs, _, err := workspaceDesigns(ws.Root, resolved)
+	if err != nil {
+		return err
+	}
+
+	duplicates := hdl.Duplicates(designs, flow)
+	if len(duplicates) == 0 {
+		color.Green("No problems found (%s)", flow)
+		return nil
+	}
+
+	printDuplicates(ws.Root, duplicates)
+	if flagLintStrict {
+		return fmt.Errorf("%d duplicate design unit(s)", len(duplicates))
+	}
+	return nil
+}
+
+// printDuplicates reports design units that are defined more than once
+func printDuplicates(root string, duplicates []hdl.Duplicate) {
+	color.Yellow("Duplicate design units (%d):", len(duplicates))
+	for _, dup := range duplicates {
+		color.Yellow("  [DUPLICATE] %s", dup.Name)
+		for _, def := range dup.Definitions {
+			fmt.Printf("    %s %s (%s)\n", def.Unit.Kind, relPath(root, def.Unit.File), def.Component)
+		}
+	}
+}
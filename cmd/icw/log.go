@@ -0,0 +1,320 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log <component>",
+	Short: "Show the release history and changelog of a component",
+	Long: `Show the tags of a component with when, by whom and with which message each
+one was created, newest first, followed by the number of commits on trunk
+that are not released yet.
+
+With --since the changelog from that tag is shown instead: the commits of
+every release after it, up to --until (a tag, or trunk by default), and the
+dependencies whose pins changed in depend.config between the two. A
+depend.config that does not parse is reported, and the nearest release with
+a valid one is compared instead.
+
+Tags may be given with or without the tags/ prefix.
+
+Examples:
+  icw log digital/spi_master                    # Release history
+  icw log digital/spi_master --since v1.0       # Changes since v1.0 on trunk
+  icw log digital/spi_master --since v1.0 --until v1.2
+  icw log digital/spi_master -r cp4             # Component in another repository`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLog(args[0])
+	},
+}
+
+// Command flags
+var (
+	flagLogSince string
+	flagLogUntil string
+	flagLogRepo  string
+)
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+
+	logCmd.Flags().StringVar(&flagLogSince, "since", "", "Show the changelog since this tag")
+	logCmd.Flags().StringVar(&flagLogUntil, "until", "", "End the changelog at this tag instead of trunk")
+	logCmd.Flags().StringVarP(&flagLogRepo, "repo", "r", "", "Repository of the component (overrides ICW_REPO/workspace.config)")
+}
+
+func runLog(componentPath string) error {
+	if flagLogUntil != "" && flagLogSince == "" {
+		return fmt.Errorf("--until requires --since")
+	}
+
+	repo, svnURL := repoConfig(flagLogRepo)
+	svnClient, err := svn.NewClientWithConfig(repo, svnURL)
+	if err != nil {
+		return fmt.Errorf("failed to create SVN client: %w", err)
+	}
+
+	tagLog, err := svnClient.Log(componentPath, "tags", true)
+	if err != nil {
+		return fmt.Errorf("failed to read tags of %s: %w", componentPath, err)
+	}
+	tags := svn.TagsFromLog(tagLog, componentPath)
+
+	if flagLogSince != "" {
+		return showChangelog(svnClient, componentPath, tags)
+	}
+	return showReleaseHistory(svnClient, componentPath, tags)
+}
+
+// showReleaseHistory prints the tags of a component, newest first
+func showReleaseHistory(svnClient *svn.Client, componentPath string, tags []svn.Tag) error {
+	color.Cyan("Component: %s", componentPath)
+	fmt.Println()
+
+	if len(tags) == 0 {
+		color.Yellow("No releases")
+	}
+	for i := len(tags) - 1; i >= 0; i-- {
+		printTagHeader(tags[i])
+		printMessage("    ", tags[i].Message)
+	}
+
+	// Commits on trunk since the newest release from trunk
+	trunk, err := svnClient.Log(componentPath, "trunk", false)
+	if err != nil {
+		return fmt.Errorf("failed to read trunk log: %w", err)
+	}
+	since := 0
+	for _, tag := range tags {
+		if tag.CopyFrom == "trunk" {
+			since = tag.CopyFromRev
+		}
+	}
+	if unreleased := svn.CommitsBetween(trunk, since, 0); len(unreleased) > 0 {
+		fmt.Println()
+		color.Yellow("%d unreleased commit(s) on trunk, see: icw log %s --since %s", len(unreleased), componentPath, latestTagName(tags))
+	}
+	return nil
+}
+
+// showChangelog prints the commits and dependency changes between --since and --until
+func showChangelog(svnClient *svn.Client, componentPath string, tags []svn.Tag) error {
+	sinceIdx := findTag(tags, flagLogSince)
+	if sinceIdx < 0 {
+		return fmt.Errorf("tag not found: %s", flagLogSince)
+	}
+	since := tags[sinceIdx]
+
+	// Releases after since, oldest first, optionally ending at until
+	releases := tags[sinceIdx+1:]
+	untilBranch := "trunk"
+	if flagLogUntil != "" {
+		untilIdx := findTag(tags, flagLogUntil)
+		if untilIdx < 0 {
+			return fmt.Errorf("tag not found: %s", flagLogUntil)
+		}
+		if untilIdx <= sinceIdx {
+			return fmt.Errorf("%s was created before %s", tags[untilIdx].Name, since.Name)
+		}
+		releases = tags[sinceIdx+1 : untilIdx+1]
+		untilBranch = tags[untilIdx].Branch()
+	}
+
+	color.Cyan("Changes in %s from %s to %s", componentPath, since.Name, strings.TrimPrefix(untilBranch, "tags/"))
+	fmt.Println()
+
+	// Logs of the branches the releases were made from, read once each
+	logs := make(map[string][]svn.LogEntry)
+	branchLog := func(branch string) ([]svn.LogEntry, error) {
+		if entries, ok := logs[branch]; ok {
+			return entries, nil
+		}
+		entries, err := svnClient.Log(componentPath, branch, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log of %s: %w", branch, err)
+		}
+		logs[branch] = entries
+		return entries, nil
+	}
+
+	// Unreleased commits first when the changelog runs up to trunk
+	if flagLogUntil == "" {
+		entries, err := branchLog("trunk")
+		if err != nil {
+			return err
+		}
+		from := since.CopyFromRev
+		if len(releases) > 0 {
+			from = releases[len(releases)-1].CopyFromRev
+		}
+		if commits := svn.CommitsBetween(entries, from, 0); len(commits) > 0 {
+			color.Yellow("trunk (unreleased)")
+			printCommits(commits)
+			fmt.Println()
+		}
+	}
+
+	for i := len(releases) - 1; i >= 0; i-- {
+		release := releases[i]
+		previous := since
+		if i > 0 {
+			previous = releases[i-1]
+		}
+
+		printTagHeader(release)
+		if release.CopyFrom == "" {
+			fmt.Println("    (not copied from a branch of the component)")
+			fmt.Println()
+			continue
+		}
+		entries, err := branchLog(release.CopyFrom)
+		if err != nil {
+			return err
+		}
+		commits := svn.CommitsBetween(entries, previous.CopyFromRev, release.CopyFromRev)
+		if len(commits) == 0 {
+			fmt.Println("    (no commits)")
+		}
+		printCommits(commits)
+		fmt.Println()
+	}
+
+	// Dependency pins in depend.config at both ends. A depend.config that
+	// does not parse is reported and the nearest release in range used instead.
+	branches := []string{since.Branch()}
+	for _, release := range releases {
+		branches = append(branches, release.Branch())
+	}
+	if flagLogUntil == "" {
+		branches = append(branches, untilBranch)
+	}
+
+	first, oldDeps, err := nearestDependencies(svnClient, componentPath, branches, 1)
+	if err != nil {
+		return err
+	}
+	last, newDeps, err := nearestDependencies(svnClient, componentPath, branches[first+1:], -1)
+	if err != nil {
+		return err
+	}
+	if first < 0 || last < 0 {
+		color.Yellow("Dependencies: unknown")
+		return nil
+	}
+	last += first + 1
+	if first > 0 || last < len(branches)-1 {
+		color.Yellow("Dependencies compared from %s to %s", strings.TrimPrefix(branches[first], "tags/"), strings.TrimPrefix(branches[last], "tags/"))
+	}
+	changes := config.DiffPins(oldDeps, newDeps)
+	if len(changes) == 0 {
+		color.Green("Dependencies: unchanged")
+		return nil
+	}
+	color.Cyan("Dependencies:")
+	for _, change := range changes {
+		switch {
+		case change.Old == "":
+			color.Green("  [ADDED] %s", change)
+		case change.New == "":
+			color.Red("  [REMOVED] %s", change)
+		default:
+			color.Yellow("  [CHANGED] %s", change)
+		}
+	}
+	return nil
+}
+
+// errInvalidDependConfig marks a depend.config that does not parse
+var errInvalidDependConfig = errors.New("invalid depend.config")
+
+// fetchDependencies reads the dependencies of a component on a branch; a
+// missing depend.config means no dependencies
+func fetchDependencies(svnClient *svn.Client, componentPath, branch string) ([]*component.Component, error) {
+	content, err := svnClient.Cat(componentPath, branch, "depend.config")
+	if errors.Is(err, svn.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read depend.config on %s: %w", branch, err)
+	}
+	deps, err := config.ParseDependencies(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w on %s: %w", errInvalidDependConfig, branch, err)
+	}
+	return deps, nil
+}
+
+// nearestDependencies returns the index in branches and the dependencies of
+// the first branch whose depend.config parses, searching forward for step 1
+// and backward for step -1. Invalid files are reported and skipped; returns
+// -1 if there is none.
+func nearestDependencies(svnClient *svn.Client, componentPath string, branches []string, step int) (int, []*component.Component, error) {
+	i := 0
+	if step < 0 {
+		i = len(branches) - 1
+	}
+	for ; i >= 0 && i < len(branches); i += step {
+		deps, err := fetchDependencies(svnClient, componentPath, branches[i])
+		if errors.Is(err, errInvalidDependConfig) {
+			color.Red("  [ERROR] %v", err)
+			continue
+		}
+		if err != nil {
+			return -1, nil, err
+		}
+		return i, deps, nil
+	}
+	return -1, nil, nil
+}
+
+// findTag returns the index of a tag given with or without the tags/ prefix, or -1
+func findTag(tags []svn.Tag, name string) int {
+	name = strings.TrimPrefix(name, "tags/")
+	for i, tag := range tags {
+		if tag.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// latestTagName returns the name of the newest tag, or "<tag>" if there is none
+func latestTagName(tags []svn.Tag) string {
+	if len(tags) == 0 {
+		return "<tag>"
+	}
+	return tags[len(tags)-1].Name
+}
+
+// printTagHeader prints the name, date, author and origin of a tag
+func printTagHeader(tag svn.Tag) {
+	origin := ""
+	if tag.CopyFrom != "" {
+		origin = fmt.Sprintf(" from %s@r%d", tag.CopyFrom, tag.CopyFromRev)
+	}
+	fmt.Printf("%s  %s  %s  r%d%s\n", color.GreenString(tag.Name), tag.Date.Local().Format("2006-01-02 15:04"), tag.Author, tag.Revision, origin)
+}
+
+// printCommits prints one line per commit
+func printCommits(commits []svn.LogEntry) {
+	for _, commit := range commits {
+		fmt.Printf("    r%d  %s  %s  %s\n", commit.Revision, commit.Date.Local().Format("2006-01-02"), commit.Author, commit.Summary())
+	}
+}
+
+// printMessage prints a commit message indented
+func printMessage(prefix, message string) {
+	for _, line := range strings.Split(strings.TrimSpace(message), "\n") {
+		fmt.Printf("%s%s\n", prefix, line)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/svn"
+)
+
+// errMetadataUnreadable marks metadata files that exist, or may exist, but
+// could not be read from the repository, e.g. because of a network error
+var errMetadataUnreadable = errors.New("could not read metadata")
+
+// fetchComponentInfo reads the metadata of a component from the repository:
+// the meta block of depend.config, overridden by component.info. Missing
+// files mean no metadata; other failures to read them are returned.
+func fetchComponentInfo(svnClient *svn.Client, componentPath, branch string) (component.Info, error) {
+	var info component.Info
+
+	content, err := svnClient.Cat(componentPath, branch, "depend.config")
+	switch {
+	case err == nil:
+		meta, err := config.ParseMeta(content)
+		if err != nil {
+			return info, fmt.Errorf("depend.config: %w", err)
+		}
+		info.Update(meta)
+	case !errors.Is(err, svn.ErrNotFound):
+		return info, fmt.Errorf("%w: %w", errMetadataUnreadable, err)
+	}
+
+	content, err = svnClient.Cat(componentPath, branch, component.InfoFile)
+	switch {
+	case err == nil:
+		parsed, err := component.ParseInfo(content)
+		if err != nil {
+			return info, fmt.Errorf("%s: %w", component.InfoFile, err)
+		}
+		info.Update(*parsed)
+	case !errors.Is(err, svn.ErrNotFound):
+		return info, fmt.Errorf("%w: %w", errMetadataUnreadable, err)
+	}
+
+	return info, nil
+}
+
+// treeComponentInfo returns the metadata of a component in the tree. The
+// depend.config content has already been read; component.info is read from
+// the checkout if the component is checked out, else from the repository.
+func treeComponentInfo(svnClient *svn.Client, comp *component.Component, dir, dependConfig string) (component.Info, error) {
+	info, err := config.ParseMeta(dependConfig)
+	if err != nil {
+		return info, fmt.Errorf("depend.config: %w", err)
+	}
+
+	var content string
+	if _, statErr := os.Stat(dir); statErr == nil {
+		data, err := os.ReadFile(filepath.Join(dir, component.InfoFile))
+		if err != nil {
+			return info, nil
+		}
+		content = string(data)
+	} else {
+		content, err = svnClient.Cat(comp.Path, comp.Branch, component.InfoFile)
+		if errors.Is(err, svn.ErrNotFound) {
+			return info, nil
+		}
+		if err != nil {
+			return info, fmt.Errorf("%w: %w", errMetadataUnreadable, err)
+		}
+	}
+
+	parsed, err := component.ParseInfo(content)
+	if err != nil {
+		return info, fmt.Errorf("%s: %w", component.InfoFile, err)
+	}
+	info.Update(*parsed)
+	return info, nil
+}
+
+// printComponentInfo prints the metadata that is set, one field per line
+func printComponentInfo(prefix string, info component.Info) {
+	if info.Description != "" {
+		fmt.Printf("%sdescription: %s\n", prefix, info.Description)
+	}
+	if info.Owner != "" {
+		fmt.Printf("%sowner: %s\n", prefix, info.Owner)
+	}
+	if info.Maturity != "" {
+		fmt.Printf("%smaturity: %s\n", prefix, maturityString(info.Maturity))
+	}
+	if len(info.Nodes) > 0 {
+		fmt.Printf("%snodes: %s\n", prefix, strings.Join(info.Nodes, ", "))
+	}
+	if info.License != "" {
+		fmt.Printf("%slicense: %s\n", prefix, info.License)
+	}
+}
+
+// maturityString colors a maturity level by how far it has been verified
+func maturityString(m component.Maturity) string {
+	switch m {
+	case component.MaturitySiliconProven:
+		return color.GreenString(string(m))
+	case component.MaturityQualified:
+		return color.CyanString(string(m))
+	}
+	return color.YellowString(string(m))
+}
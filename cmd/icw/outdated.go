@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/semver"
+	"github.com/spf13/cobra"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List tag-pinned components with newer releases",
+	Long: `Compare every component of the workspace that is pinned to a tag with the
+newest release of that component in the repository.
+
+  [OUTDATED]  A newer release exists
+  [MISSING]   The pinned tag does not exist in the repository
+  [OK]        The pinned tag is the newest release (only with --all)
+
+Tags are compared as versions, so v1.10 is newer than v1.9, and pre-releases
+such as v2.0-rc1 are not offered as upgrades of a release. Components on
+trunk or a branch are not checked.
+
+Dependencies are read from the checked out components, so run 'icw update'
+first to check the complete dependency tree.
+
+Examples:
+  icw outdated                         # Components with newer releases
+  icw outdated --all                   # Include up-to-date components`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runOutdated()
+	},
+}
+
+// Command flags
+var (
+	flagOutdatedAll bool
+)
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+
+	outdatedCmd.Flags().BoolVarP(&flagOutdatedAll, "all", "a", false, "Also list components that are up to date")
+}
+
+func runOutdated() error {
+	_, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	var pinned []*component.Component
+	for _, comp := range resolved {
+		if comp.VCS == "svn" && strings.HasPrefix(comp.Branch, "tags/") {
+			pinned = append(pinned, comp)
+		}
+	}
+	if len(pinned) == 0 {
+		color.Yellow("No components are pinned to a tag")
+		return nil
+	}
+	sort.Slice(pinned, func(i, j int) bool { return pinned[i].Name < pinned[j].Name })
+
+	svnClient, err := newSVNClient(parser)
+	if err != nil {
+		return err
+	}
+
+	outdated := 0
+	for _, comp := range pinned {
+		current := strings.TrimPrefix(comp.Branch, "tags/")
+		declaredBy := comp.DeclaredBy
+		if declaredBy == "" {
+			declaredBy = "workspace.config"
+		}
+
+		tags, err := svnClient.ListTags(comp.Path)
+		if err != nil {
+			color.Red("  [ERROR] %s: %v", comp.Name, err)
+			continue
+		}
+		latest := semver.Latest(tags)
+
+		switch {
+		case !slices.Contains(tags, current):
+			outdated++
+			color.Red("  [MISSING] %s %s (latest %s, declared by %s)", comp.Name, current, latestOrNone(latest), declaredBy)
+		case semver.Compare(current, latest) < 0:
+			outdated++
+			color.Yellow("  [OUTDATED] %s %s -> %s (declared by %s)", comp.Name, current, latest, declaredBy)
+		case flagOutdatedAll:
+			color.Green("  [OK] %s %s", comp.Name, current)
+		}
+	}
+
+	fmt.Println()
+	if outdated == 0 {
+		color.Green("All %d tag-pinned components are up to date", len(pinned))
+	} else {
+		color.Yellow("%d of %d tag-pinned components are outdated", outdated, len(pinned))
+	}
+	return nil
+}
+
+// latestOrNone returns the latest tag, or "none" if there are no tags
+func latestOrNone(latest string) string {
+	if latest == "" {
+		return "none"
+	}
+	return latest
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/cadence"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/svn"
+)
+
+// processComponent returns the single process component of the workspace,
+// nil if there is none, or an error if more than one is declared
+func processComponent(comps []*component.Component) (*component.Component, error) {
+	var found []*component.Component
+	for _, comp := range comps {
+		if comp.Type == component.TypeProcess {
+			found = append(found, comp)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	}
+
+	var names []string
+	for _, comp := range found {
+		names = append(names, fmt.Sprintf("%s (declared by %s)", comp.Name, comp.DeclaredBy))
+	}
+	return nil, fmt.Errorf("only one process component is allowed in a workspace, found %d:\n  %s",
+		len(found), strings.Join(names, "\n  "))
+}
+
+// updateProcessSetup links the process component into the workspace root
+func updateProcessSetup(root string, comps []*component.Component) error {
+	proc, err := processComponent(comps)
+	if err != nil {
+		return err
+	}
+	if proc == nil || !svn.IsWorkingCopy(filepath.Join(root, proc.Path)) {
+		return nil
+	}
+
+	actions, err := cadence.LinkProcessSetup(root, proc.Path)
+	if err != nil {
+		return fmt.Errorf("failed to set up process %s: %w", proc.Name, err)
+	}
+	for _, action := range actions {
+		color.Cyan("  [PROCESS] %s", action)
+	}
+	return nil
+}
+
+// printProcessStatus reports which PDK is active in the workspace
+func printProcessStatus(root string, comps []*component.Component) {
+	proc, err := processComponent(comps)
+	if err != nil {
+		color.Red("[ERROR] %v", err)
+		fmt.Println()
+		return
+	}
+	if proc == nil {
+		return
+	}
+
+	version := cadence.PDKVersion(root)
+	if version == "" {
+		version = "unknown (no pdk_version, run icw update)"
+	}
+	color.Cyan("Process: %s (%s)", proc.Name, proc.Branch)
+	color.Cyan("PDK version: %s\n", version)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove components that are no longer declared",
+	Long: `Find working copies under analog/, digital/ and setup/ that are not part of
+the resolved dependency tree any more and remove them.
+
+Orphaned working copies with local modifications or unversioned files are
+kept unless --force is given. Use --dry-run to only list orphans.
+
+The same cleanup runs after an update with 'icw update --prune'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPrune()
+	},
+}
+
+// Command flags
+var (
+	flagPruneDryRun bool
+	flagPruneForce  bool
+)
+
+// pruneRoots are the workspace directories searched for orphaned working copies
+var pruneRoots = []string{"analog", "digital", "setup"}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+
+	pruneCmd.Flags().BoolVar(&flagPruneDryRun, "dry-run", false, "List orphaned components without removing them")
+	pruneCmd.Flags().BoolVarP(&flagPruneForce, "force", "f", false, "Remove orphaned components even if they have local changes")
+}
+
+func runPrune() error {
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	// Dependencies of components that are not checked out are unknown,
+	// so pruning could remove something that is still needed
+	for _, comp := range resolved {
+		if comp.VCS == "svn" && !svn.IsWorkingCopy(filepath.Join(ws.Root, comp.Path)) {
+			return fmt.Errorf("%s is not checked out, run 'icw update' before pruning", comp.Name)
+		}
+	}
+
+	svnClient, err := newSVNClient(parser)
+	if err != nil {
+		return err
+	}
+
+	return pruneWorkspace(ws.Root, resolved, svnClient, flagPruneDryRun, flagPruneForce)
+}
+
+// pruneWorkspace removes working copies that are not among the declared components
+func pruneWorkspace(root string, declared []*component.Component, svnClient *svn.Client, dryRun, force bool) error {
+	declaredPaths := make(map[string]bool)
+	for _, comp := range declared {
+		declaredPaths[filepath.Clean(comp.Path)] = true
+	}
+
+	workingCopies, err := findWorkingCopies(root, pruneRoots)
+	if err != nil {
+		return err
+	}
+
+	var orphans []string
+	for _, wc := range workingCopies {
+		if !declaredPaths[wc] {
+			orphans = append(orphans, wc)
+		}
+	}
+
+	if len(orphans) == 0 {
+		color.Green("No orphaned components found")
+		return nil
+	}
+
+	color.Cyan("Orphaned components (%d):", len(orphans))
+	kept := 0
+	for _, orphan := range orphans {
+		destPath := filepath.Join(root, orphan)
+
+		clean, _, err := svnClient.IsClean(destPath)
+		if err != nil {
+			color.Red("  [ERROR] %s: %v", orphan, err)
+			kept++
+			continue
+		}
+
+		if dryRun {
+			if clean {
+				color.Yellow("  [PRUNE] %s", orphan)
+			} else {
+				color.Yellow("  [PRUNE] %s (has local changes)", orphan)
+			}
+			continue
+		}
+
+		if !clean && !force {
+			color.Red("  [KEEP] %s (has local changes, use --force to remove)", orphan)
+			kept++
+			continue
+		}
+
+		color.Yellow("  [PRUNE] %s", orphan)
+		if err := os.RemoveAll(destPath); err != nil {
+			color.Red("    Failed: %v", err)
+			kept++
+		}
+	}
+
+	if dryRun {
+		fmt.Println()
+		color.Yellow("[DRY RUN] Run without --dry-run to remove")
+		return nil
+	}
+
+	if kept > 0 {
+		return fmt.Errorf("%d orphaned component(s) were kept", kept)
+	}
+	return nil
+}
+
+// findWorkingCopies returns the workspace-relative paths of all working copy
+// roots below the given top-level directories
+func findWorkingCopies(root string, dirs []string) ([]string, error) {
+	var found []string
+
+	for _, dir := range dirs {
+		top := filepath.Join(root, dir)
+		if _, err := os.Stat(top); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(top, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			// The type directory itself is never a component
+			if path != top && svn.IsWorkingCopy(path) {
+				found = append(found, relPath(root, path))
+				// Do not descend into the working copy itself
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(found)
+	return found, nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var relocateCmd = &cobra.Command{
+	Use:   "relocate",
+	Short: "Move the workspace to a new SVN server URL",
+	Long: `Relocate all working copies in the workspace to a new SVN server URL
+and update "set svn_url" in workspace.config.
+
+--from defaults to the SVN URL currently used by the workspace.
+
+Examples:
+  icw relocate --to svn://g9
+  icw relocate --from svn://anyvej11.dk --to svn://g9
+  icw relocate --from svn://g9 --to svn://anyvej11.dk --dry-run`,
+	RunE: runRelocate,
+}
+
+// Command flags
+var (
+	flagRelocateFrom   string
+	flagRelocateTo     string
+	flagRelocateDryRun bool
+)
+
+func init() {
+	rootCmd.AddCommand(relocateCmd)
+
+	relocateCmd.Flags().StringVar(&flagRelocateFrom, "from", "", "Current SVN server URL (default: workspace SVN URL)")
+	relocateCmd.Flags().StringVar(&flagRelocateTo, "to", "", "New SVN server URL")
+	relocateCmd.Flags().BoolVar(&flagRelocateDryRun, "dry-run", false, "Show what would be done without doing it")
+	relocateCmd.MarkFlagRequired("to")
+}
+
+func runRelocate(cmd *cobra.Command, args []string) error {
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	svnClient, err := newSVNClient(parser)
+	if err != nil {
+		return err
+	}
+
+	from := strings.TrimSuffix(flagRelocateFrom, "/")
+	if from == "" {
+		from = strings.TrimSuffix(svnClient.URL, "/")
+	}
+	to := strings.TrimSuffix(flagRelocateTo, "/")
+
+	if from == to {
+		return fmt.Errorf("--from and --to are the same URL: %s", to)
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	if flagRelocateDryRun {
+		color.Cyan("[DRY RUN] Relocate %s → %s\n", from, to)
+	} else {
+		color.Cyan("Relocate %s → %s\n", from, to)
+	}
+
+	relocated := 0
+	failed := 0
+	for _, comp := range resolved {
+		if comp.VCS != "svn" {
+			continue
+		}
+
+		destPath := filepath.Join(ws.Root, comp.Path)
+		if !svn.IsWorkingCopy(destPath) {
+			continue
+		}
+
+		url, err := svnClient.WorkingCopyURL(destPath)
+		if err != nil {
+			color.Red("  [ERROR] %s: %v", comp.Name, err)
+			failed++
+			continue
+		}
+
+		if !strings.HasPrefix(url, from+"/") {
+			if strings.HasPrefix(url, to+"/") {
+				color.Green("  [OK] %s (already at %s)", comp.Name, to)
+			} else {
+				color.Yellow("  [SKIP] %s (checked out from %s)", comp.Name, url)
+			}
+			continue
+		}
+
+		newURL := to + strings.TrimPrefix(url, from)
+		if flagRelocateDryRun {
+			color.Yellow("  [RELOCATE] %s", comp.Name)
+			fmt.Printf("    %s\n    → %s\n", url, newURL)
+			relocated++
+			continue
+		}
+
+		color.Yellow("  [RELOCATE] %s", comp.Name)
+		if err := svnClient.Relocate(from, to, destPath); err != nil {
+			color.Red("    Failed: %v", err)
+			failed++
+			continue
+		}
+		relocated++
+	}
+
+	fmt.Println()
+	if flagRelocateDryRun {
+		color.Yellow("[DRY RUN] Would relocate %d working copy(s)", relocated)
+		color.Yellow("[DRY RUN] Would set svn_url \"%s\" in %s", to, ws.Config)
+		return nil
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d working copy(s) could not be relocated, workspace.config not changed", failed)
+	}
+
+	if err := config.SetWorkspaceOption(ws.Config, "svn_url", to); err != nil {
+		return err
+	}
+
+	color.Green("Relocated %d working copy(s)", relocated)
+	color.Green("Updated svn_url in %s", ws.Config)
+	return nil
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/search"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search components by name, description and owner",
+	Long: `Search all components in the repository by name and by the metadata in their
+component.info file or the meta block of their depend.config:
+
+  description: SPI master with configurable CPOL/CPHA
+  owner: jdoe
+  maturity: qualified
+  nodes: tsmc28, gf22
+
+The query is a glob pattern with *, ? and [...] wildcards, matched against
+the full name (digital/spi_master), the name without type (spi_master), the
+description and the owner, ignoring case. A query without wildcards matches
+anywhere, so "fifo" finds every component mentioning a FIFO. Use --regex for
+a regular expression instead.
+
+Searches use an index cached in ~/.icw/cache, which is rebuilt from the
+repository once a day or with --refresh. With --offline, or when the
+repository cannot be reached, the cached index is used as it is.
+
+Results can be narrowed to a maturity (experimental, qualified or
+silicon-proven) with --maturity and to a supported process node with --node.
+
+Examples:
+  icw search spi                       # Name, description or owner contains spi
+  icw search "spi_*" -t digital        # Digital components starting with spi_
+  icw search --owner jdoe              # Components owned by jdoe
+  icw search --maturity silicon-proven --node tsmc28
+  icw search -e "^(uart|spi)_"         # Regular expression
+  icw search fifo --offline            # Use the cached index only`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := ""
+		if len(args) > 0 {
+			query = args[0]
+		}
+		return runSearch(query)
+	},
+}
+
+// Command flags
+var (
+	flagSearchTypes    []string
+	flagSearchOwner    string
+	flagSearchMaturity string
+	flagSearchNode     string
+	flagSearchRegex    bool
+	flagSearchRefresh  bool
+	flagSearchOffline  bool
+	flagSearchRepo     string
+)
+
+// searchWorkers is the number of components whose metadata is fetched in parallel
+const searchWorkers = 8
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().StringSliceVarP(&flagSearchTypes, "type", "t", nil, "Only search these component types (analog, digital, setup, process)")
+	searchCmd.Flags().StringVar(&flagSearchOwner, "owner", "", "Only match the owner field")
+	searchCmd.Flags().StringVar(&flagSearchMaturity, "maturity", "", "Only components of this maturity (experimental, qualified, silicon-proven)")
+	searchCmd.Flags().StringVar(&flagSearchNode, "node", "", "Only components supporting this process node")
+	searchCmd.Flags().BoolVarP(&flagSearchRegex, "regex", "e", false, "Treat the query as a regular expression")
+	searchCmd.Flags().BoolVar(&flagSearchRefresh, "refresh", false, "Rebuild the index from the repository")
+	searchCmd.Flags().BoolVar(&flagSearchOffline, "offline", false, "Only use the cached index")
+	searchCmd.Flags().StringVarP(&flagSearchRepo, "repo", "r", "", "Repository to search (overrides ICW_REPO/workspace.config)")
+}
+
+func runSearch(query string) error {
+	if query != "" && flagSearchOwner != "" {
+		return fmt.Errorf("give either a query or --owner")
+	}
+
+	q, err := newSearchQuery(query)
+	if err != nil {
+		return err
+	}
+
+	idx, err := loadSearchIndex()
+	if err != nil {
+		return err
+	}
+
+	results := idx.Search(q)
+	if len(results) == 0 {
+		color.Yellow("No components match %s", searchDescription(query))
+		return nil
+	}
+
+	for _, entry := range results {
+		if entry.Owner != "" {
+			fmt.Printf("%s  [%s]\n", color.GreenString(entry.Name), entry.Owner)
+		} else {
+			color.Green(entry.Name)
+		}
+		if entry.Description != "" {
+			fmt.Printf("    %s\n", entry.Description)
+		}
+		if details := searchDetails(entry); details != "" {
+			fmt.Printf("    %s\n", details)
+		}
+	}
+	fmt.Println()
+	color.Cyan("Found %d component(s) (index of %s from %s)", len(results), idx.Repo, idx.Updated.Local().Format("2006-01-02 15:04"))
+	return nil
+}
+
+// newSearchQuery builds the query from the argument and the filter flags
+func newSearchQuery(query string) (*search.Query, error) {
+	var fields []string
+	if flagSearchOwner != "" {
+		query = flagSearchOwner
+		fields = []string{search.FieldOwner}
+	}
+	if query == "" {
+		query = "*"
+	}
+
+	q, err := search.NewQuery(query, flagSearchRegex)
+	if err != nil {
+		return nil, err
+	}
+	q.Fields = fields
+	q.Types = flagSearchTypes
+	q.Node = flagSearchNode
+	if flagSearchMaturity != "" {
+		maturity, err := component.ParseMaturity(flagSearchMaturity)
+		if err != nil {
+			return nil, err
+		}
+		q.Maturity = string(maturity)
+	}
+	return q, nil
+}
+
+// searchDetails formats the maturity, process nodes and license of a result
+func searchDetails(entry search.Entry) string {
+	var details []string
+	if entry.Maturity != "" {
+		details = append(details, maturityString(component.Maturity(entry.Maturity)))
+	}
+	if len(entry.Nodes) > 0 {
+		details = append(details, "nodes: "+strings.Join(entry.Nodes, ", "))
+	}
+	if entry.License != "" {
+		details = append(details, "license: "+entry.License)
+	}
+	return strings.Join(details, "  ")
+}
+
+// searchDescription describes the query in messages
+func searchDescription(query string) string {
+	var parts []string
+	if query != "" {
+		parts = append(parts, query)
+	}
+	if flagSearchOwner != "" {
+		parts = append(parts, "owner "+flagSearchOwner)
+	}
+	if flagSearchMaturity != "" {
+		parts = append(parts, "maturity "+flagSearchMaturity)
+	}
+	if flagSearchNode != "" {
+		parts = append(parts, "node "+flagSearchNode)
+	}
+	if len(parts) == 0 {
+		return "*"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// loadSearchIndex returns the cached index of the repository, rebuilding it
+// first if it is missing, older than a day or --refresh is given
+func loadSearchIndex() (*search.Index, error) {
+	repo, svnURL := repoConfig(flagSearchRepo)
+	if repo == "" {
+		repo = os.Getenv("ICW_REPO")
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("no repository configured; use --repo, set ICW_REPO or add to workspace.config: set repo \"repo_name\"")
+	}
+
+	path, err := search.IndexPath(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, loadErr := search.Load(path)
+	if flagSearchOffline || (loadErr == nil && !flagSearchRefresh && !cached.Stale(time.Now())) {
+		if loadErr != nil {
+			return nil, fmt.Errorf("no cached index for %s, run icw search without --offline first", repo)
+		}
+		if cached.Outdated() {
+			color.Yellow("The cached index has no maturity, nodes or license, run icw search --refresh to update it")
+		}
+		return cached, nil
+	}
+
+	idx, failed, err := buildSearchIndex(repo, svnURL)
+	if err != nil {
+		if loadErr == nil {
+			color.Yellow("Could not update the index, using the cached one: %v", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	// An index with gaps would be trusted for a day, so it is only used once
+	if failed > 0 {
+		color.Yellow("The index is incomplete (%d failed read(s)) and is not saved", failed)
+		return idx, nil
+	}
+
+	if err := idx.Save(path); err != nil {
+		color.Yellow("Could not save the index: %v", err)
+	}
+	return idx, nil
+}
+
+// buildSearchIndex lists all components in the repository and reads their
+// metadata from trunk. Also returns the number of component types that could
+// not be listed and components whose metadata could not be read.
+func buildSearchIndex(repo, svnURL string) (*search.Index, int, error) {
+	svnClient, err := svn.NewClientWithConfig(repo, svnURL)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create SVN client: %w", err)
+	}
+
+	// A type that cannot be listed leaves a gap like unreadable metadata
+	var entries []search.Entry
+	var failed atomic.Int32
+	var lastErr error
+	for _, typ := range component.RepositoryTypes {
+		components, err := svnClient.ListComponentsByType(string(typ))
+		if err != nil {
+			color.Yellow("  [%s] Could not list: %v", typ, err)
+			failed.Add(1)
+			lastErr = err
+			continue
+		}
+		for _, name := range components {
+			entries = append(entries, search.Entry{Name: name, Type: string(typ)})
+		}
+	}
+	if int(failed.Load()) == len(component.RepositoryTypes) {
+		return nil, 0, fmt.Errorf("could not list any component type: %w", lastErr)
+	}
+
+	color.Yellow("Indexing %d components in %s...", len(entries), svnClient.Repo)
+
+	// Components without metadata are indexed by name only
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < searchWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				info, err := fetchComponentInfo(svnClient, entries[i].Name, "trunk")
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "icw: %s: %v\n", entries[i].Name, err)
+					if errors.Is(err, errMetadataUnreadable) {
+						failed.Add(1)
+					}
+				}
+				entries[i].Description = info.Description
+				entries[i].Owner = info.Owner
+				entries[i].Maturity = string(info.Maturity)
+				entries[i].Nodes = info.Nodes
+				entries[i].License = info.License
+			}
+		}()
+	}
+	for i := range entries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return &search.Index{Repo: svnClient.Repo, URL: svnClient.URL, Updated: time.Now().UTC(), Entries: entries}, int(failed.Load()), nil
+}
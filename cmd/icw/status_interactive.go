@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/svn"
+)
+
+// statusGroups holds the svn status entries of a component grouped by state
+type statusGroups struct {
+	modified    []svn.StatusEntry
+	unversioned []svn.StatusEntry
+	artefacts   []svn.StatusEntry // Unversioned files matching svn.ArtefactPatterns
+	missing     []svn.StatusEntry
+	conflicted  []svn.StatusEntry
+}
+
+func groupStatus(entries []svn.StatusEntry) statusGroups {
+	var groups statusGroups
+	for _, entry := range entries {
+		switch {
+		case entry.Code == 'C':
+			groups.conflicted = append(groups.conflicted, entry)
+		case entry.Code == '!':
+			groups.missing = append(groups.missing, entry)
+		case entry.IsUnversioned() && svn.IsArtefact(entry.Path):
+			groups.artefacts = append(groups.artefacts, entry)
+		case entry.IsUnversioned():
+			groups.unversioned = append(groups.unversioned, entry)
+		case entry.IsModified():
+			groups.modified = append(groups.modified, entry)
+		}
+	}
+	return groups
+}
+
+// errQuit stops the interactive walk through the workspace
+var errQuit = errors.New("quit")
+
+// runInteractiveStatus walks all components with changes and lets the user
+// add, ignore, revert or skip each entry
+func runInteractiveStatus(root string, comps []*component.Component, svnClient *svn.Client) error {
+	color.Cyan("Workspace status (interactive):\n")
+	fmt.Println("Answer with a lower-case letter for a single file, or upper-case to")
+	fmt.Println("apply the same action to the remaining entries in that directory.")
+	fmt.Println()
+
+	hasChanges := false
+	for _, comp := range comps {
+		if comp.VCS != "svn" {
+			continue
+		}
+
+		destPath := filepath.Join(root, comp.Path)
+		if !svn.IsWorkingCopy(destPath) {
+			color.Yellow("[NOT CHECKED OUT] %s", comp.Name)
+			hasChanges = true
+			continue
+		}
+
+		status, err := svnClient.Status(destPath)
+		if err != nil {
+			color.Red("[ERROR] %s: %v", comp.Name, err)
+			continue
+		}
+
+		entries := svn.ParseStatus(status)
+		if len(entries) == 0 {
+			color.Green("[CLEAN] %s (%s)", comp.Name, comp.Branch)
+			continue
+		}
+		hasChanges = true
+
+		color.Yellow("[MODIFIED] %s (%s)", comp.Name, comp.Branch)
+		groups := groupStatus(entries)
+		printStatusGroup("Conflicted", groups.conflicted, destPath)
+		printStatusGroup("Modified", groups.modified, destPath)
+		printStatusGroup("Missing", groups.missing, destPath)
+		printStatusGroup("Unversioned", groups.unversioned, destPath)
+		printStatusGroup("Tool artefacts", groups.artefacts, destPath)
+
+		if err := resolveStatusGroups(groups, destPath, svnClient); err != nil {
+			if err == errQuit {
+				return nil
+			}
+			return err
+		}
+		fmt.Println()
+	}
+
+	if !hasChanges {
+		fmt.Println()
+		color.Green("Workspace is clean - no changes detected")
+	}
+
+	return nil
+}
+
+func printStatusGroup(title string, entries []svn.StatusEntry, base string) {
+	if len(entries) == 0 {
+		return
+	}
+	color.Cyan("  %s (%d):", title, len(entries))
+	for _, entry := range entries {
+		fmt.Printf("    %c %s\n", entry.Code, relPath(base, entry.Path))
+	}
+}
+
+func resolveStatusGroups(groups statusGroups, base string, svnClient *svn.Client) error {
+	if len(groups.conflicted) > 0 {
+		color.Red("  Conflicts must be resolved by hand (svn resolve) before committing")
+	}
+
+	// Declined artefacts are asked about one by one like other unversioned files
+	if len(groups.artefacts) > 0 {
+		if askYesNo(fmt.Sprintf("  Add %d tool artefact(s) to svn:ignore?", len(groups.artefacts)), true) {
+			for _, entry := range groups.artefacts {
+				applyStatusAction('i', entry, svnClient)
+			}
+		} else {
+			groups.unversioned = append(groups.unversioned, groups.artefacts...)
+		}
+	}
+
+	if err := promptStatusEntries(groups.unversioned, base, "[s]kip [a]dd [i]gnore [q]uit", "saiqSAI", svnClient); err != nil {
+		return err
+	}
+	if err := promptStatusEntries(groups.missing, base, "[s]kip [r]evert [q]uit", "srqSR", svnClient); err != nil {
+		return err
+	}
+	return promptStatusEntries(groups.modified, base, "[s]kip [r]evert [q]uit", "srqSR", svnClient)
+}
+
+// promptStatusEntries asks for an action per entry. Upper-case answers are
+// remembered for the entry's directory and applied without asking again.
+func promptStatusEntries(entries []svn.StatusEntry, base, prompt, choices string, svnClient *svn.Client) error {
+	dirAction := make(map[string]byte)
+
+	for _, entry := range entries {
+		dir := filepath.Dir(entry.Path)
+
+		action, ok := dirAction[dir]
+		if !ok {
+			answer := askChoice(fmt.Sprintf("  %c %s  %s?", entry.Code, relPath(base, entry.Path), prompt), choices)
+			if answer == 'q' {
+				return errQuit
+			}
+			if answer >= 'A' && answer <= 'Z' {
+				answer += 'a' - 'A'
+				dirAction[dir] = answer
+			}
+			action = answer
+		}
+
+		applyStatusAction(action, entry, svnClient)
+	}
+
+	return nil
+}
+
+func applyStatusAction(action byte, entry svn.StatusEntry, svnClient *svn.Client) {
+	var err error
+	switch action {
+	case 'a':
+		err = svnClient.AddPath(entry.Path)
+		if err == nil {
+			color.Green("    added %s", filepath.Base(entry.Path))
+		}
+	case 'i':
+		err = svnClient.AddIgnore(filepath.Dir(entry.Path), filepath.Base(entry.Path))
+		if err == nil {
+			color.Green("    ignored %s", filepath.Base(entry.Path))
+		}
+	case 'r':
+		err = svnClient.RevertEntry(entry)
+		if err == nil {
+			color.Green("    reverted %s", filepath.Base(entry.Path))
+		}
+	}
+
+	if err != nil {
+		color.Red("    Failed: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/svn"
+)
+
+// switchComponent moves a working copy from its current branch to the declared one.
+// Local modifications block the switch unless --stash is given.
+func switchComponent(comp *component.Component, current, root, destPath string, svnClient *svn.Client) error {
+	color.Yellow("  [SWITCH] %s (%s → %s)", comp.Name, current, comp.Branch)
+
+	status, err := svnClient.Status(destPath)
+	if err != nil {
+		return err
+	}
+
+	entries := svn.ParseStatus(status)
+	modified := false
+	for _, entry := range entries {
+		if entry.Code == 'C' {
+			return fmt.Errorf("not switching %s: working copy has conflicts", comp.Name)
+		}
+		if entry.IsModified() {
+			modified = true
+		}
+	}
+
+	if modified {
+		if !flagUpdateStash {
+			return fmt.Errorf("not switching %s: working copy has local modifications (commit them or use --stash)", comp.Name)
+		}
+
+		patchPath, archivePath, err := stashChanges(comp, root, destPath, entries, svnClient)
+		if err != nil {
+			return fmt.Errorf("failed to stash local modifications: %w", err)
+		}
+		color.Cyan("    Local modifications stashed to %s", relPath(root, patchPath))
+		color.Cyan("    Re-apply with: svn patch %s %s", relPath(root, patchPath), comp.Path)
+		color.Cyan("    Changed and added files, including binary ones, saved to %s", relPath(root, archivePath))
+	}
+
+	if err := svnClient.Switch(comp.Path, comp.Branch, destPath); err != nil {
+		return fmt.Errorf("switch failed: %w", err)
+	}
+
+	return nil
+}
+
+// stashChanges saves the local modifications of a working copy under
+// <root>/.icw/stash and reverts them. svn diff leaves out binary files such
+// as Cadence views, so the changed and added files are also archived whole
+// in the format of icw wipe.
+func stashChanges(comp *component.Component, root, destPath string, entries []svn.StatusEntry, svnClient *svn.Client) (string, string, error) {
+	diff, err := svnClient.Diff(destPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	stashDir := filepath.Join(root, ".icw", "stash")
+	if err := os.MkdirAll(stashDir, 0755); err != nil {
+		return "", "", err
+	}
+
+	name := fmt.Sprintf("%s-%s", strings.ReplaceAll(comp.Name, "/", "_"), time.Now().Format("20060102-150405"))
+	patchPath := filepath.Join(stashDir, name+".patch")
+	if err := os.WriteFile(patchPath, []byte(diff), 0644); err != nil {
+		return "", "", err
+	}
+
+	// Unversioned files survive the revert and need no saving
+	var changed []svn.StatusEntry
+	for _, entry := range entries {
+		if entry.IsModified() {
+			changed = append(changed, entry)
+		}
+	}
+	archivePath := filepath.Join(stashDir, name+".tar.gz")
+	if _, err := writeChangesArchive(archivePath, root, map[string][]svn.StatusEntry{comp.Name: changed}); err != nil {
+		return patchPath, "", fmt.Errorf("failed to archive changed files: %w", err)
+	}
+
+	// Only revert once the patch and the archive are safely on disk
+	if err := svnClient.Revert(destPath); err != nil {
+		return patchPath, archivePath, err
+	}
+
+	return patchPath, archivePath, nil
+}
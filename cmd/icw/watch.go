@@ -0,0 +1,358 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/export"
+	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/jakobsen/icw/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Regenerate file lists when HDL files change",
+	Long: `Watch the digital components of the workspace and rewrite the generated
+files declared in workspace.config whenever HDL files are added, removed or
+changed, or when workspace.config or a depend.config changes.
+
+Outputs are declared with the export targets (see 'icw export --help'),
+a path relative to the workspace root and an optional flow:
+
+  output filelist "build/rtl.f"
+  output make "build/syn.mk" synthesis
+  output tcl "build/files.tcl"
+
+Only the components with changed files are searched again, and an output is
+only written when its content changes, so make does not rebuild needlessly.
+Components that are not checked out yet are picked up when they appear.
+
+Watching uses inotify and is only available on Linux. Stop it with Ctrl-C.
+
+Examples:
+  icw watch                            # Keep outputs up to date
+  icw watch --once                     # Write outputs once and exit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runWatch()
+	},
+}
+
+// Command flags
+var (
+	flagWatchOnce bool
+)
+
+// watchDebounce is how long to wait for further changes before regenerating,
+// since editors and svn update change several files at once
+const watchDebounce = 200 * time.Millisecond
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().BoolVar(&flagWatchOnce, "once", false, "Write the outputs once and exit")
+}
+
+// hdlWatch is the state of a running watch: the resolved workspace and the
+// discovered HDL files of each component
+type hdlWatch struct {
+	ws       *component.Workspace
+	outputs  []config.Output
+	comps    []*component.Component // Digital components, dependencies first
+	files    map[string]*hdl.HDLFiles
+	watcher  *watch.Watcher
+	watching map[string]bool // Component directories being watched
+	missing  map[string]bool // Component directories not checked out
+}
+
+func runWatch() error {
+	w := &hdlWatch{
+		files:    make(map[string]*hdl.HDLFiles),
+		watching: make(map[string]bool),
+		missing:  make(map[string]bool),
+	}
+	if err := w.load(); err != nil {
+		return err
+	}
+	if len(w.outputs) == 0 {
+		return fmt.Errorf("no outputs declared in workspace.config, e.g.: output filelist \"build/rtl.f\"")
+	}
+
+	if err := w.generate(); err != nil {
+		return err
+	}
+	if flagWatchOnce {
+		return nil
+	}
+
+	watcher, err := watch.New()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	w.watcher = watcher
+
+	if err := watcher.Add(w.ws.Root); err != nil {
+		return err
+	}
+	if err := w.watchComponents(); err != nil {
+		return err
+	}
+
+	color.Cyan("Watching %d components (%d directories), press Ctrl-C to stop", len(w.comps), watcher.Len())
+
+	for {
+		events, err := watcher.Wait(time.Hour)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		// Collect the rest of a burst of changes
+		for {
+			more, err := watcher.Wait(watchDebounce)
+			if err != nil {
+				return err
+			}
+			if len(more) == 0 {
+				break
+			}
+			events = append(events, more...)
+		}
+
+		if err := w.apply(events); err != nil {
+			color.Red("  [ERROR] %v", err)
+		}
+	}
+}
+
+// load parses the workspace configuration and resolves the dependency graph
+func (w *hdlWatch) load() error {
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	w.ws = ws
+	w.outputs = parser.Outputs
+	w.comps = nil
+	for _, comp := range component.DependencyOrder(resolved) {
+		if isHDLComponent(comp) {
+			w.comps = append(w.comps, comp)
+		}
+	}
+
+	// Forget components that are no longer part of the workspace
+	current := make(map[string]bool)
+	for _, comp := range w.comps {
+		current[comp.Name] = true
+	}
+	for name := range w.files {
+		if !current[name] {
+			delete(w.files, name)
+		}
+	}
+	return nil
+}
+
+// watchComponents watches the directories of all checked out components.
+// For components that are not checked out, the nearest existing parent
+// directory is watched so that their checkout is noticed.
+func (w *hdlWatch) watchComponents() error {
+	for _, comp := range w.comps {
+		dir := filepath.Join(w.ws.Root, comp.Path)
+		if w.watching[dir] {
+			continue
+		}
+
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			if err := w.watcher.AddTree(dir, nil); err != nil {
+				return err
+			}
+			w.watching[dir] = true
+			if w.missing[dir] {
+				delete(w.missing, dir)
+				delete(w.files, comp.Name) // Checked out since it was last searched
+			}
+			continue
+		}
+		w.missing[dir] = true
+
+		for parent := filepath.Dir(dir); strings.HasPrefix(parent, w.ws.Root); parent = filepath.Dir(parent) {
+			if _, err := os.Stat(parent); err == nil {
+				if err := w.watcher.Add(parent); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// apply updates the workspace for a batch of changes and rewrites the outputs
+func (w *hdlWatch) apply(events []watch.Event) error {
+	reload := false
+	newDirs := false
+	changed := make(map[string]bool)
+
+	for _, event := range events {
+		if event.Op == watch.Overflow {
+			// Changes were lost, search everything again
+			reload = true
+			for _, comp := range w.comps {
+				changed[comp.Name] = true
+			}
+			continue
+		}
+
+		name := filepath.Base(event.Path)
+		if name == "workspace.config" && filepath.Dir(event.Path) == w.ws.Root {
+			reload = true
+			continue
+		}
+
+		comp := w.componentOf(event.Path)
+		if event.IsDir && event.Op == watch.Remove && w.watching[event.Path] {
+			// The component was removed, e.g. by icw wipe; notice a new checkout
+			delete(w.watching, event.Path)
+			newDirs = true
+		}
+		if event.IsDir && event.Op == watch.Create {
+			newDirs = true
+			if comp != nil {
+				if err := w.watcher.AddTree(event.Path, nil); err != nil {
+					return err
+				}
+			}
+		}
+		if comp == nil {
+			continue
+		}
+
+		switch {
+		case name == "depend.config":
+			reload = true
+			changed[comp.Name] = true
+		case event.IsDir, name == hdl.IgnoreFile, hdl.IsHDLFile(name):
+			changed[comp.Name] = true
+		}
+	}
+
+	if !reload && !newDirs && len(changed) == 0 {
+		return nil
+	}
+
+	if reload {
+		if err := w.load(); err != nil {
+			return err
+		}
+	}
+	if reload || newDirs {
+		if err := w.watchComponents(); err != nil {
+			return err
+		}
+	}
+
+	for name := range changed {
+		delete(w.files, name)
+	}
+
+	var names []string
+	for name := range changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > 0 {
+		fmt.Printf("%s changed: %s\n", time.Now().Format("15:04:05"), strings.Join(names, ", "))
+	} else if reload {
+		fmt.Printf("%s changed: workspace.config\n", time.Now().Format("15:04:05"))
+	}
+
+	return w.generate()
+}
+
+// componentOf returns the component whose directory contains path, or nil
+func (w *hdlWatch) componentOf(path string) *component.Component {
+	var found *component.Component
+	longest := 0
+	for _, comp := range w.comps {
+		dir := filepath.Join(w.ws.Root, comp.Path)
+		if (path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))) && len(dir) > longest {
+			found = comp
+			longest = len(dir)
+		}
+	}
+	return found
+}
+
+// discover returns the HDL files of a component, searching its directory
+// only if it changed since the last search
+func (w *hdlWatch) discover(comp *component.Component) (*hdl.HDLFiles, error) {
+	if files, ok := w.files[comp.Name]; ok {
+		return files, nil
+	}
+	files, err := discoverComponentHDL(w.ws.Root, comp)
+	if err != nil {
+		return nil, err
+	}
+	w.files[comp.Name] = files
+	return files, nil
+}
+
+// generate writes every output whose content changed
+func (w *hdlWatch) generate() error {
+	var roots []*component.Component
+	for _, comp := range w.ws.Ordered() {
+		if comp.IsTopLevel() && comp.Type == component.TypeDigital {
+			roots = append(roots, comp)
+		}
+	}
+	name := filepath.Base(w.ws.Root)
+
+	for _, output := range w.outputs {
+		flow := output.Flow
+		if flow == "" {
+			flow = defaultExportFlow(output.Target)
+		}
+
+		project, err := buildProject(w.ws.Root, name, roots, flow, "", w.discover)
+		if err != nil {
+			return err
+		}
+		content, err := export.Render(output.Target, project)
+		if err != nil {
+			return err
+		}
+
+		path := output.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(w.ws.Root, path)
+		}
+		if existing, err := os.ReadFile(path); err == nil && string(existing) == content {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output.Path, err)
+		}
+		color.Green("  [WRITE] %s", relPath(w.ws.Root, path))
+	}
+	return nil
+}
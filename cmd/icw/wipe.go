@@ -0,0 +1,292 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/svn"
+	"github.com/spf13/cobra"
+)
+
+var wipeCmd = &cobra.Command{
+	Use:   "wipe [component...]",
+	Short: "Reset components to a clean checkout",
+	Long: `Remove components from the workspace and check them out again at the
+branch declared in workspace.config or depend.config.
+
+Components with local modifications or unversioned files are refused unless
+--force is given, as are component directories that are not SVN working
+copies, e.g. a broken checkout. Before deleting a dirty component the modified
+and unversioned files, or all files of a directory that is not a working copy,
+can be saved to a timestamped tarball in the workspace root.
+
+Examples:
+  icw wipe                      # Reset the whole workspace
+  icw wipe digital/top          # Reset a single component
+  icw wipe digital/top --force  # Reset even with local changes (offers archive)
+  icw wipe --force --archive    # Archive local changes without asking`,
+	RunE: runWipe,
+}
+
+// Command flags
+var (
+	flagWipeForce     bool
+	flagWipeArchive   bool
+	flagWipeNoArchive bool
+	flagWipeYes       bool
+)
+
+func init() {
+	rootCmd.AddCommand(wipeCmd)
+
+	wipeCmd.Flags().BoolVarP(&flagWipeForce, "force", "f", false, "Wipe components even if they have local changes")
+	wipeCmd.Flags().BoolVar(&flagWipeArchive, "archive", false, "Archive local changes without asking")
+	wipeCmd.Flags().BoolVar(&flagWipeNoArchive, "no-archive", false, "Do not archive local changes")
+	wipeCmd.Flags().BoolVarP(&flagWipeYes, "yes", "y", false, "Do not ask for confirmation")
+}
+
+func runWipe(cmd *cobra.Command, args []string) error {
+	if flagWipeArchive && flagWipeNoArchive {
+		return fmt.Errorf("--archive and --no-archive are mutually exclusive")
+	}
+
+	ws, parser, err := loadWorkspace()
+	if err != nil {
+		return err
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		return err
+	}
+
+	selected, err := selectComponents(resolved, args)
+	if err != nil {
+		return err
+	}
+
+	svnClient, err := newSVNClient(parser)
+	if err != nil {
+		return err
+	}
+
+	// Only SVN components can be wiped and checked out again
+	var targets []*component.Component
+	for _, comp := range selected {
+		if comp.VCS != "svn" {
+			color.Blue("  [SKIP] %s (%s)", comp.Name, comp.VCS)
+			continue
+		}
+		targets = append(targets, comp)
+	}
+
+	if len(targets) == 0 {
+		color.Yellow("No components to wipe")
+		return nil
+	}
+
+	// Find local changes before touching anything. A directory that is not a
+	// working copy has no status, so all of its files count as unversioned.
+	dirty := make(map[string][]svn.StatusEntry)
+	notWorkingCopy := make(map[string]bool)
+	for _, comp := range targets {
+		destPath := filepath.Join(ws.Root, comp.Path)
+		if !svn.IsWorkingCopy(destPath) {
+			if _, err := os.Lstat(destPath); err == nil {
+				dirty[comp.Name] = []svn.StatusEntry{{Code: '?', Path: destPath}}
+				notWorkingCopy[comp.Name] = true
+			}
+			continue
+		}
+
+		clean, entries, err := svnClient.IsClean(destPath)
+		if err != nil {
+			return fmt.Errorf("failed to get status of %s: %w", comp.Name, err)
+		}
+		if !clean {
+			dirty[comp.Name] = entries
+		}
+	}
+
+	if len(dirty) > 0 {
+		color.Yellow("Components with local changes:")
+		for _, comp := range targets {
+			entries, ok := dirty[comp.Name]
+			if !ok {
+				continue
+			}
+			if notWorkingCopy[comp.Name] {
+				color.Yellow("  %s (not a working copy, all files)", comp.Name)
+				continue
+			}
+			color.Yellow("  %s", comp.Name)
+			for _, entry := range entries {
+				fmt.Printf("    %c %s\n", entry.Code, relPath(ws.Root, entry.Path))
+			}
+		}
+		fmt.Println()
+
+		if !flagWipeForce {
+			return fmt.Errorf("%d component(s) have local changes or are not working copies, use --force to wipe anyway", len(dirty))
+		}
+
+		archive := flagWipeArchive
+		if !archive && !flagWipeNoArchive {
+			archive = askYesNo("Archive modified and unversioned files first?", true)
+		}
+		if archive {
+			archivePath := filepath.Join(ws.Root, fmt.Sprintf("icw-wipe-%s.tar.gz", time.Now().Format("20060102-150405")))
+			count, err := writeChangesArchive(archivePath, ws.Root, dirty)
+			if err != nil {
+				return fmt.Errorf("failed to archive local changes: %w", err)
+			}
+			color.Green("Archived %d file(s) to %s", count, archivePath)
+		}
+	}
+
+	if !flagWipeYes {
+		question := fmt.Sprintf("Wipe and check out %d component(s) again?", len(targets))
+		if len(notWorkingCopy) > 0 {
+			var names []string
+			for _, comp := range targets {
+				if notWorkingCopy[comp.Name] {
+					names = append(names, comp.Name)
+				}
+			}
+			question = fmt.Sprintf("Wipe and check out %d component(s) again, including directories that are not working copies (%s)?", len(targets), strings.Join(names, ", "))
+		}
+		if !askYesNo(question, false) {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	failed := 0
+	for _, comp := range targets {
+		destPath := filepath.Join(ws.Root, comp.Path)
+		color.Yellow("  [WIPE] %s (%s)", comp.Name, comp.Branch)
+
+		if err := os.RemoveAll(destPath); err != nil {
+			color.Red("    Failed to remove: %v", err)
+			failed++
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			color.Red("    Failed to create directory: %v", err)
+			failed++
+			continue
+		}
+
+		if err := svnClient.Checkout(comp.Path, comp.Branch, destPath); err != nil {
+			color.Red("    Failed: %v", err)
+			failed++
+			continue
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d component(s) could not be checked out again", failed)
+	}
+
+	color.Green("\nWipe complete!")
+	return nil
+}
+
+// writeChangesArchive stores modified and unversioned files in a gzipped tarball.
+// Paths in the archive are relative to the workspace root.
+func writeChangesArchive(archivePath, root string, dirty map[string][]svn.StatusEntry) (int, error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	count := 0
+	for _, entries := range dirty {
+		for _, entry := range entries {
+			// Deleted or missing files have nothing to save
+			if entry.Code == 'D' || !(entry.IsUnversioned() || entry.IsModified()) {
+				continue
+			}
+
+			info, err := os.Lstat(entry.Path)
+			if err != nil {
+				continue
+			}
+
+			// Modified directories only carry property changes, but an
+			// unversioned directory is saved with all of its content
+			if info.IsDir() && !entry.IsUnversioned() {
+				continue
+			}
+
+			err = filepath.Walk(entry.Path, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.Mode().IsRegular() {
+					return nil
+				}
+				if err := addFileToArchive(tw, path, root, info); err != nil {
+					return err
+				}
+				count++
+				return nil
+			})
+			if err != nil {
+				return count, err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return count, err
+	}
+	if err := gz.Close(); err != nil {
+		return count, err
+	}
+	// The deferred Close only cleans up after errors; a failed final write
+	// would leave a truncated archive
+	if err := file.Close(); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// addFileToArchive writes a single regular file to the tar stream
+func addFileToArchive(tw *tar.Writer, path, root string, info os.FileInfo) error {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(rel)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(tw, src)
+	return err
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/config"
+	"github.com/jakobsen/icw/internal/svn"
+)
+
+// loadWorkspace finds the workspace root and parses workspace.config
+func loadWorkspace() (*component.Workspace, *config.Parser, error) {
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("not in a workspace: %w", err)
+	}
+
+	ws := component.NewWorkspace(root)
+	parser := config.NewParser(ws)
+	if err := parser.ParseWorkspaceConfig(ws.Config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse workspace.config: %w", err)
+	}
+
+	return ws, parser, nil
+}
+
+// newSVNClient creates an SVN client using the repository settings from workspace.config
+func newSVNClient(parser *config.Parser) (*svn.Client, error) {
+	svnClient, err := svn.NewClientWithConfig(parser.Repo, parser.SvnURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SVN client: %w", err)
+	}
+	return svnClient, nil
+}
+
+// repoConfig returns the repository to use outside of a workspace command:
+// repoFlag if set, else the repository of the current workspace, if any.
+// Empty values fall back to the environment in the SVN client.
+func repoConfig(repoFlag string) (repo, svnURL string) {
+	if repoFlag != "" {
+		return repoFlag, ""
+	}
+
+	root, err := config.FindWorkspaceRoot()
+	if err != nil {
+		return "", ""
+	}
+	ws := component.NewWorkspace(root)
+	parser := config.NewParser(ws)
+	if err := parser.ParseWorkspaceConfig(ws.Config); err != nil {
+		return "", ""
+	}
+	return parser.Repo, parser.SvnURL
+}
+
+// selectComponents returns the components named in args, or all components if args is empty
+func selectComponents(resolved []*component.Component, args []string) ([]*component.Component, error) {
+	if len(args) == 0 {
+		return resolved, nil
+	}
+
+	byName := make(map[string]*component.Component)
+	for _, comp := range resolved {
+		byName[comp.Name] = comp
+	}
+
+	var selected []*component.Component
+	for _, name := range args {
+		name = strings.TrimSuffix(name, "/")
+		comp, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("component %s is not declared in the workspace", name)
+		}
+		selected = append(selected, comp)
+	}
+	return selected, nil
+}
+
+// relPath returns path relative to the workspace root, or path itself if that is not possible
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
+
+// stdin is shared by all prompts so buffered input is not lost between questions
+var stdin = bufio.NewReader(os.Stdin)
+
+// askYesNo prompts the user and returns the answer, using def when the user just presses Enter
+func askYesNo(prompt string, def bool) bool {
+	if def {
+		fmt.Printf("%s [Y/n] ", prompt)
+	} else {
+		fmt.Printf("%s [y/N] ", prompt)
+	}
+
+	response, _ := stdin.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	if response == "" {
+		return def
+	}
+	return response == "y" || response == "yes"
+}
+
+// askChoice prompts until the user answers with one of the characters in choices.
+// The first character of choices is used when the user just presses Enter.
+func askChoice(prompt, choices string) byte {
+	for {
+		fmt.Printf("%s ", prompt)
+		response, err := stdin.ReadString('\n')
+		response = strings.TrimSpace(response)
+
+		if len(response) == 1 && strings.IndexByte(choices, response[0]) >= 0 {
+			return response[0]
+		}
+		// Empty answer or end of input selects the default
+		if response == "" || err != nil {
+			return choices[0]
+		}
+		fmt.Printf("Please answer one of: %s\n", strings.Join(strings.Split(choices, ""), ", "))
+	}
+}
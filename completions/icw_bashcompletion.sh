@@ -7,7 +7,7 @@ _icw_complete() {
     prev="${COMP_WORDS[COMP_CWORD-1]}"
 
     # Current Go commands (implemented)
-    commands="update status st tree hdl add version test list ls migrate auth completion help"
+    commands="update status st tree hdl add version test list ls migrate auth wipe relocate commit ci prune env lint export watch search log outdated completion help"
 
     # Note: 'migrate' command requires MAW backend (only works on g9 server)
 
@@ -17,6 +17,22 @@ _icw_complete() {
     # Command-specific flags
     local list_flags="-t --type -b --branches -g --tags -a --all -r --repo"
     local migrate_flags="--create-repo --from --to --add-user --dry-run"
+    local wipe_flags="-f --force --archive --no-archive -y --yes"
+    local relocate_flags="--from --to --dry-run"
+    local commit_flags="-m --message -y --yes"
+    local update_flags="--stash --prune --no-hooks --hook-timeout"
+    local prune_flags="--dry-run -f --force"
+    local status_flags="-i --interactive --offline"
+    local env_flags="--shell"
+    local hdl_flags="--flow --list --options --strict --manifest"
+    local hdl_diff_flags="--json"
+    local lint_flags="--flow --strict"
+    local export_flags="-t --target --flow -o --output --top"
+    local watch_flags="--once"
+    local search_flags="-t --type --owner --maturity --node -e --regex --refresh --offline -r --repo"
+    local tree_flags="-l --long"
+    local log_flags="--since --until -r --repo"
+    local outdated_flags="-a --all"
 
     # Get the main command (first word after icw)
     local command=""
@@ -36,6 +52,10 @@ _icw_complete() {
     # Flag value completion based on previous word
     case "${prev}" in
         -t|--type)
+            if [[ "${command}" == "export" ]]; then
+                COMPREPLY=( $(compgen -W "filelist verilator yosys openroad fusesoc edalize make tcl" -- ${cur}) )
+                return 0
+            fi
             # Component types for list command
             COMPREPLY=( $(compgen -W "analog digital setup process" -- ${cur}) )
             return 0
@@ -45,6 +65,26 @@ _icw_complete() {
             # For now, just return to let user type freely
             return 0
             ;;
+        --target)
+            COMPREPLY=( $(compgen -W "filelist verilator yosys openroad fusesoc edalize make tcl" -- ${cur}) )
+            return 0
+            ;;
+        --manifest)
+            COMPREPLY=( $(compgen -f -- ${cur}) )
+            return 0
+            ;;
+        --flow)
+            COMPREPLY=( $(compgen -W "synthesis rtl-sim gate-sim" -- ${cur}) )
+            return 0
+            ;;
+        --maturity)
+            COMPREPLY=( $(compgen -W "experimental qualified silicon-proven" -- ${cur}) )
+            return 0
+            ;;
+        --shell)
+            COMPREPLY=( $(compgen -W "bash zsh csh fish json" -- ${cur}) )
+            return 0
+            ;;
         --add-user)
             # Usernames - could be enhanced to list actual users
             # For now, just return to let user type freely
@@ -70,6 +110,29 @@ _icw_complete() {
             fi
             return 0
             ;;
+        wipe)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${wipe_flags} ${global_flags}" -- ${cur}) )
+            else
+                # Complete with component directories in the workspace
+                COMPREPLY=( $(compgen -d -- ${cur}) )
+            fi
+            return 0
+            ;;
+        relocate)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${relocate_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        commit|ci)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${commit_flags} ${global_flags}" -- ${cur}) )
+            else
+                COMPREPLY=( $(compgen -d -- ${cur}) )
+            fi
+            return 0
+            ;;
         add)
             # First argument: directory completion
             if [[ $COMP_CWORD -eq 2 ]]; then
@@ -83,7 +146,89 @@ _icw_complete() {
             fi
             return 0
             ;;
-        update|status|st|tree|hdl|test|version)
+        status|st)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${status_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        update)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${update_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        prune)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${prune_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        env)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${env_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        hdl)
+            if [[ "${COMP_WORDS[2]}" == "diff" && $COMP_CWORD -gt 2 ]]; then
+                if [[ ${cur} == -* ]]; then
+                    COMPREPLY=( $(compgen -W "${hdl_diff_flags} ${global_flags}" -- ${cur}) )
+                else
+                    COMPREPLY=( $(compgen -f -- ${cur}) )
+                fi
+            elif [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${hdl_flags} ${global_flags}" -- ${cur}) )
+            elif [[ $COMP_CWORD -eq 2 ]]; then
+                COMPREPLY=( $(compgen -W "check diff" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        lint)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${lint_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        export)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${export_flags} ${global_flags}" -- ${cur}) )
+            else
+                COMPREPLY=( $(compgen -d -- ${cur}) )
+            fi
+            return 0
+            ;;
+        search)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${search_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        log)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${log_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        outdated)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${outdated_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        watch)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${watch_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        tree)
+            if [[ ${cur} == -* ]]; then
+                COMPREPLY=( $(compgen -W "${tree_flags} ${global_flags}" -- ${cur}) )
+            fi
+            return 0
+            ;;
+        test|version)
             # These commands only have global flags
             if [[ ${cur} == -* ]]; then
                 COMPREPLY=( $(compgen -W "${global_flags}" -- ${cur}) )
@@ -0,0 +1,179 @@
+package cadence
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// Markers delimiting the part of local.lib that icw owns
+const (
+	ManagedBegin = "# BEGIN ICW MANAGED LIBRARIES - regenerated by icw update, do not edit"
+	ManagedEnd   = "# END ICW MANAGED LIBRARIES"
+)
+
+// Library is a Cadence library definition (DEFINE <name> <path>)
+type Library struct {
+	Name    string // Library name
+	Path    string // Library path, relative to the workspace root for components
+	Comment string // Trailing comment (revision information)
+	Source  string // Component or file that defines the library
+}
+
+var definePattern = regexp.MustCompile(`^\s*(?i:DEFINE)\s+(\S+)\s+(\S+)`)
+
+// LibrariesFromComponents returns the library definitions for all analog
+// components and local references in the workspace
+func LibrariesFromComponents(comps []*component.Component) []Library {
+	var libs []Library
+	for _, comp := range comps {
+		switch {
+		case comp.VCS == "local":
+			libs = append(libs, Library{
+				Name:    filepath.Base(comp.Path),
+				Path:    comp.Path,
+				Comment: "local reference",
+				Source:  comp.Name,
+			})
+		case comp.Type == component.TypeAnalog:
+			libs = append(libs, Library{
+				Name:    filepath.Base(comp.Path),
+				Path:    comp.Path,
+				Comment: fmt.Sprintf("revision %s from %s", comp.Branch, comp.Path),
+				Source:  comp.Name,
+			})
+		}
+	}
+	return libs
+}
+
+// WriteLocalLib regenerates the managed block of a local.lib file with the
+// given libraries. Lines outside the block are preserved as they are.
+// Libraries that are defined more than once are reported as warnings; the
+// first managed definition is kept.
+func WriteLocalLib(path string, libs []Library) ([]string, error) {
+	var before, after []string
+
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err == nil {
+		before, after = splitManaged(string(content))
+	}
+
+	var warnings []string
+
+	// Libraries the user defined by hand outside the managed block
+	userDefined := make(map[string]bool)
+	for _, userLines := range [][]string{before, after} {
+		for _, line := range userLines {
+			if matches := definePattern.FindStringSubmatch(line); matches != nil {
+				userDefined[matches[1]] = true
+			}
+		}
+	}
+
+	block := []string{ManagedBegin}
+	defined := make(map[string]Library)
+	for _, lib := range libs {
+		if first, ok := defined[lib.Name]; ok {
+			if first.Path != lib.Path {
+				warnings = append(warnings, fmt.Sprintf("library %s defined twice: %s (%s) and %s (%s), using %s",
+					lib.Name, first.Path, first.Source, lib.Path, lib.Source, first.Path))
+			}
+			continue
+		}
+		defined[lib.Name] = lib
+
+		if userDefined[lib.Name] {
+			warnings = append(warnings, fmt.Sprintf("library %s from %s is also defined outside the icw block in %s",
+				lib.Name, lib.Source, filepath.Base(path)))
+		}
+
+		line := fmt.Sprintf("DEFINE %s %s", lib.Name, lib.Path)
+		if lib.Comment != "" {
+			line += " #" + lib.Comment
+		}
+		block = append(block, line)
+	}
+	block = append(block, ManagedEnd)
+
+	// before and after share one backing array, so build a new slice
+	var lines []string
+	lines = append(lines, before...)
+	lines = append(lines, block...)
+	lines = append(lines, after...)
+	output := strings.Join(lines, "\n") + "\n"
+
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return warnings, fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+
+	return warnings, nil
+}
+
+// splitManaged returns the lines before and after the managed block.
+// Without a managed block all existing lines are kept before it.
+func splitManaged(content string) ([]string, []string) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil, nil
+	}
+
+	begin, end := -1, -1
+	for i, line := range lines {
+		switch strings.TrimSpace(line) {
+		case ManagedBegin:
+			begin = i
+		case ManagedEnd:
+			if begin >= 0 {
+				end = i
+			}
+		}
+	}
+
+	if begin < 0 || end < 0 {
+		return lines, nil
+	}
+	return lines[:begin], lines[end+1:]
+}
+
+// EnsureInclude makes sure cds.lib includes the given file, creating cds.lib
+// if it does not exist. Returns true if cds.lib was changed.
+func EnsureInclude(cdsLibPath, include string) (bool, error) {
+	content, err := os.ReadFile(cdsLibPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read cds.lib: %w", err)
+	}
+
+	if hasInclude(string(content), include) {
+		return false, nil
+	}
+
+	text := string(content)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += "SOFTINCLUDE " + include + "\n"
+
+	if err := os.WriteFile(cdsLibPath, []byte(text), 0644); err != nil {
+		return false, fmt.Errorf("failed to write cds.lib: %w", err)
+	}
+	return true, nil
+}
+
+// hasInclude reports whether cds.lib content already includes the given file
+func hasInclude(content, include string) bool {
+	includePattern := regexp.MustCompile(`^\s*(?i:SOFTINCLUDE|INCLUDE)\s+\S*` + regexp.QuoteMeta(include) + `\s*$`)
+	for _, line := range strings.Split(content, "\n") {
+		if includePattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
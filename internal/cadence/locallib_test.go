@@ -0,0 +1,115 @@
+package cadence
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+func TestLibrariesFromComponents(t *testing.T) {
+	comps := []*component.Component{
+		{Name: "analog/bias", Path: "analog/bias", Type: component.TypeAnalog, Branch: "tags/v1.0", VCS: "svn"},
+		{Name: "digital/top", Path: "digital/top", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"},
+		{Name: "/home/jkj/work/ic_pwm_blocks", Path: "/home/jkj/work/ic_pwm_blocks", Type: component.TypeDigital, Branch: "local", VCS: "local"},
+	}
+
+	libs := LibrariesFromComponents(comps)
+	if len(libs) != 2 {
+		t.Fatalf("Expected 2 libraries, got %d", len(libs))
+	}
+	if libs[0].Name != "bias" || libs[0].Path != "analog/bias" {
+		t.Errorf("Unexpected library: %+v", libs[0])
+	}
+	if !strings.Contains(libs[0].Comment, "tags/v1.0") {
+		t.Errorf("Expected revision in comment, got %q", libs[0].Comment)
+	}
+	if libs[1].Name != "ic_pwm_blocks" || libs[1].Path != "/home/jkj/work/ic_pwm_blocks" {
+		t.Errorf("Unexpected library: %+v", libs[1])
+	}
+}
+
+func TestWriteLocalLib(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "local.lib")
+
+	existing := `DEFINE my_scratch /home/jkj/scratch
+` + ManagedBegin + `
+DEFINE old_lib analog/old_lib #revision trunk from analog/old_lib
+` + ManagedEnd + `
+DEFINE bias /tmp/my_bias
+`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to create local.lib: %v", err)
+	}
+
+	libs := []Library{
+		{Name: "bias", Path: "analog/bias", Comment: "revision tags/v1.0 from analog/bias", Source: "analog/bias"},
+		{Name: "opamp", Path: "analog/opamp", Source: "analog/opamp"},
+		{Name: "opamp", Path: "analog/cells/opamp", Source: "analog/cells/opamp"},
+	}
+
+	warnings, err := WriteLocalLib(path, libs)
+	if err != nil {
+		t.Fatalf("WriteLocalLib failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	expected := `DEFINE my_scratch /home/jkj/scratch
+` + ManagedBegin + `
+DEFINE bias analog/bias #revision tags/v1.0 from analog/bias
+DEFINE opamp analog/opamp
+` + ManagedEnd + `
+DEFINE bias /tmp/my_bias
+`
+	if string(content) != expected {
+		t.Errorf("Unexpected local.lib:\n%s\nexpected:\n%s", content, expected)
+	}
+
+	// bias is also defined by hand, opamp comes from two components
+	if len(warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %d: %v", len(warnings), warnings)
+	}
+}
+
+func TestWriteLocalLibNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "local.lib")
+
+	warnings, err := WriteLocalLib(path, []Library{{Name: "bias", Path: "analog/bias"}})
+	if err != nil {
+		t.Fatalf("WriteLocalLib failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	content, _ := os.ReadFile(path)
+	expected := ManagedBegin + "\nDEFINE bias analog/bias\n" + ManagedEnd + "\n"
+	if string(content) != expected {
+		t.Errorf("Unexpected local.lib:\n%s", content)
+	}
+}
+
+func TestEnsureInclude(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cds.lib")
+	if err := os.WriteFile(path, []byte("DEFINE analogLib $CDSHOME/tools/dfII/etc/cdslib/artist/analogLib"), 0644); err != nil {
+		t.Fatalf("Failed to create cds.lib: %v", err)
+	}
+
+	changed, err := EnsureInclude(path, "local.lib")
+	if err != nil || !changed {
+		t.Fatalf("Expected cds.lib to be changed, got changed=%v err=%v", changed, err)
+	}
+
+	// Second call must not add the include again
+	changed, err = EnsureInclude(path, "local.lib")
+	if err != nil || changed {
+		t.Fatalf("Expected cds.lib to be unchanged, got changed=%v err=%v", changed, err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if strings.Count(string(content), "SOFTINCLUDE local.lib") != 1 {
+		t.Errorf("Expected exactly one include, got:\n%s", content)
+	}
+}
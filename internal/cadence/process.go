@@ -0,0 +1,129 @@
+package cadence
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProcessSetupDir is the workspace entry pointing at the active process component
+const ProcessSetupDir = "process_setup"
+
+// processLinks are files in the process component linked into the workspace root
+var processLinks = []string{".cdsinit", "pdk_version"}
+
+// LinkProcessSetup points process_setup at the process component checked out at
+// processPath (relative to root), symlinks .cdsinit and pdk_version into the
+// workspace root and includes the process cds.lib from the workspace cds.lib.
+// Returns a description of each action taken.
+func LinkProcessSetup(root, processPath string) ([]string, error) {
+	var actions []string
+
+	changed, err := replaceSymlink(filepath.Join(root, ProcessSetupDir), processPath)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		actions = append(actions, fmt.Sprintf("%s -> %s", ProcessSetupDir, processPath))
+	}
+
+	for _, name := range processLinks {
+		if _, err := os.Stat(filepath.Join(root, processPath, name)); err != nil {
+			continue
+		}
+
+		target := filepath.Join(ProcessSetupDir, name)
+		changed, err := replaceSymlink(filepath.Join(root, name), target)
+		if err != nil {
+			return actions, err
+		}
+		if changed {
+			actions = append(actions, fmt.Sprintf("%s -> %s", name, target))
+		}
+	}
+
+	// The workspace cds.lib belongs to the user, who may add libraries to it,
+	// so the process cds.lib is included rather than copied over it
+	src := filepath.Join(root, processPath, "cds.lib")
+	if _, err := os.Stat(src); err == nil {
+		changed, err := includeProcessLib(filepath.Join(root, "cds.lib"), src)
+		if err != nil {
+			return actions, err
+		}
+		if changed {
+			actions = append(actions, fmt.Sprintf("cds.lib includes %s", processLibInclude))
+		}
+	}
+
+	return actions, nil
+}
+
+// processLibInclude is the process cds.lib as included from the workspace cds.lib
+var processLibInclude = filepath.Join(ProcessSetupDir, "cds.lib")
+
+// includeProcessLib adds an include of the process cds.lib at the top of the
+// workspace cds.lib. Workspaces set up by earlier versions hold a copy of the
+// process cds.lib instead, which is replaced by the include; lines the user
+// added after the copy are kept. Returns true if cds.lib was changed.
+func includeProcessLib(cdsLibPath, processLib string) (bool, error) {
+	content, err := os.ReadFile(cdsLibPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read cds.lib: %w", err)
+	}
+	if hasInclude(string(content), processLibInclude) {
+		return false, nil
+	}
+
+	text := string(content)
+	if copied, err := os.ReadFile(processLib); err == nil && len(copied) > 0 {
+		text = strings.TrimPrefix(text, string(copied))
+	}
+	text = "SOFTINCLUDE " + processLibInclude + "\n" + text
+
+	// Replace a symlinked cds.lib instead of writing through it
+	if info, err := os.Lstat(cdsLibPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		os.Remove(cdsLibPath)
+	}
+	if err := os.WriteFile(cdsLibPath, []byte(text), 0644); err != nil {
+		return false, fmt.Errorf("failed to write cds.lib: %w", err)
+	}
+	return true, nil
+}
+
+// replaceSymlink makes link a symlink to target. Existing symlinks are
+// replaced, but a real file or directory may hold the user's own setup, so
+// it is left alone and reported as an error.
+func replaceSymlink(link, target string) (bool, error) {
+	info, err := os.Lstat(link)
+	if err == nil {
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			if current, _ := os.Readlink(link); current == target {
+				return false, nil
+			}
+		case info.IsDir():
+			return false, fmt.Errorf("%s is a directory, remove it to let icw manage the process setup", filepath.Base(link))
+		default:
+			return false, fmt.Errorf("%s is a file, move it away to let icw manage the process setup", filepath.Base(link))
+		}
+		if err := os.Remove(link); err != nil {
+			return false, err
+		}
+	}
+
+	if err := os.Symlink(target, link); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PDKVersion returns the content of the workspace's pdk_version file,
+// or an empty string if no process setup is active
+func PDKVersion(root string) string {
+	content, err := os.ReadFile(filepath.Join(root, "pdk_version"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.SplitN(string(content), "\n", 2)[0])
+}
@@ -0,0 +1,113 @@
+package cadence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLinkProcessSetup(t *testing.T) {
+	root := t.TempDir()
+	processPath := "process/sky130A"
+
+	files := map[string]string{
+		".cdsinit":    "load(\"pdk.il\")\n",
+		"pdk_version": "sky130A 1.0.3\n",
+		"cds.lib":     "DEFINE sky130_fd_pr $PDK_ROOT/sky130_fd_pr\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, processPath, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	actions, err := LinkProcessSetup(root, processPath)
+	if err != nil {
+		t.Fatalf("LinkProcessSetup failed: %v", err)
+	}
+	if len(actions) != 4 {
+		t.Errorf("Expected 4 actions, got %d: %v", len(actions), actions)
+	}
+
+	if target, _ := os.Readlink(filepath.Join(root, "process_setup")); target != processPath {
+		t.Errorf("Expected process_setup -> %s, got %s", processPath, target)
+	}
+	if target, _ := os.Readlink(filepath.Join(root, ".cdsinit")); target != "process_setup/.cdsinit" {
+		t.Errorf("Expected .cdsinit -> process_setup/.cdsinit, got %s", target)
+	}
+
+	if version := PDKVersion(root); version != "sky130A 1.0.3" {
+		t.Errorf("Expected PDK version 'sky130A 1.0.3', got %q", version)
+	}
+
+	cdsLib := filepath.Join(root, "cds.lib")
+	if content, _ := os.ReadFile(cdsLib); string(content) != "SOFTINCLUDE process_setup/cds.lib\n" {
+		t.Errorf("Unexpected cds.lib: %q", content)
+	}
+
+	// Running again keeps the user's edits to cds.lib
+	edited := "SOFTINCLUDE process_setup/cds.lib\nDEFINE mylib ./mylib\n"
+	os.WriteFile(cdsLib, []byte(edited), 0644)
+	actions, err = LinkProcessSetup(root, processPath)
+	if err != nil {
+		t.Fatalf("Second LinkProcessSetup failed: %v", err)
+	}
+	if len(actions) != 0 {
+		t.Errorf("Expected no actions, got %v", actions)
+	}
+	if content, _ := os.ReadFile(cdsLib); string(content) != edited {
+		t.Errorf("cds.lib was changed: %q", content)
+	}
+}
+
+func TestLinkProcessSetupCopiedCdsLib(t *testing.T) {
+	root := t.TempDir()
+	processPath := "process/sky130A"
+	processLib := "DEFINE sky130_fd_pr $PDK_ROOT/sky130_fd_pr\n"
+	os.MkdirAll(filepath.Join(root, processPath), 0755)
+	os.WriteFile(filepath.Join(root, processPath, "cds.lib"), []byte(processLib), 0644)
+
+	// A copy made by an earlier version, with an include added after it
+	cdsLib := filepath.Join(root, "cds.lib")
+	os.WriteFile(cdsLib, []byte(processLib+"SOFTINCLUDE local.lib\n"), 0644)
+
+	if _, err := LinkProcessSetup(root, processPath); err != nil {
+		t.Fatalf("LinkProcessSetup failed: %v", err)
+	}
+	want := "SOFTINCLUDE process_setup/cds.lib\nSOFTINCLUDE local.lib\n"
+	if content, _ := os.ReadFile(cdsLib); string(content) != want {
+		t.Errorf("cds.lib = %q, want %q", content, want)
+	}
+}
+
+func TestLinkProcessSetupLegacyDirectory(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "process_setup"), 0755)
+
+	if _, err := LinkProcessSetup(root, "process/sky130A"); err == nil {
+		t.Error("Expected error when process_setup is a real directory")
+	}
+}
+
+func TestLinkProcessSetupKeepsFiles(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "process", "sky130A"), 0755)
+	os.WriteFile(filepath.Join(root, "process", "sky130A", ".cdsinit"), []byte("; process\n"), 0644)
+	cdsinit := filepath.Join(root, ".cdsinit")
+	os.WriteFile(cdsinit, []byte("; my settings\n"), 0644)
+
+	if _, err := LinkProcessSetup(root, "process/sky130A"); err == nil {
+		t.Error("Expected error when .cdsinit is a regular file")
+	}
+	if content, err := os.ReadFile(cdsinit); err != nil || string(content) != "; my settings\n" {
+		t.Errorf("User .cdsinit must be kept, got %q, %v", content, err)
+	}
+}
+
+func TestPDKVersionMissing(t *testing.T) {
+	if version := PDKVersion(t.TempDir()); version != "" {
+		t.Errorf("Expected empty PDK version, got %q", version)
+	}
+}
@@ -0,0 +1,50 @@
+package component
+
+import (
+	"path"
+	"slices"
+)
+
+// InheritedIncdirs returns the include directories of comp and all of its
+// transitive dependencies relative to the workspace root, dependencies first
+// and without duplicates. Absolute directories are kept as they are.
+func InheritedIncdirs(comp *Component) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, c := range DependencyOrder([]*Component{comp}) {
+		for _, dir := range c.Incdirs {
+			if !path.IsAbs(dir) {
+				dir = path.Join(c.Path, dir)
+			}
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// InheritedDefines returns the defines of comp and all of its transitive
+// dependencies, dependencies first. A define of a using component replaces
+// the define of the same name from its dependencies.
+func InheritedDefines(comp *Component) []Define {
+	var defines []Define
+	for _, c := range DependencyOrder([]*Component{comp}) {
+		defines = MergeDefines(defines, c.Defines...)
+	}
+	return defines
+}
+
+// MergeDefines adds defines in order, replacing an earlier define of the
+// same name in place
+func MergeDefines(defines []Define, add ...Define) []Define {
+	for _, def := range add {
+		if i := slices.IndexFunc(defines, func(d Define) bool { return d.Name == def.Name }); i >= 0 {
+			defines[i] = def
+			continue
+		}
+		defines = append(defines, def)
+	}
+	return defines
+}
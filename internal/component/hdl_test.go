@@ -0,0 +1,43 @@
+package component
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInheritedHDLSettings(t *testing.T) {
+	lib := &Component{
+		Name:    "digital/lib",
+		Path:    "digital/lib",
+		Incdirs: []string{"include", "/opt/pdk/verilog"},
+		Defines: []Define{{Name: "WIDTH", Value: "8"}, {Name: "ASYNC_RESET"}},
+	}
+	spi := &Component{
+		Name:         "digital/spi",
+		Path:         "digital/spi",
+		Incdirs:      []string{"rtl/inc", "../lib/include"},
+		Dependencies: []*Component{lib},
+	}
+	top := &Component{
+		Name:         "digital/top",
+		Path:         "digital/top",
+		Defines:      []Define{{Name: "WIDTH", Value: "16"}},
+		Dependencies: []*Component{spi, lib},
+	}
+
+	incdirs := InheritedIncdirs(top)
+	expectedDirs := []string{"digital/lib/include", "/opt/pdk/verilog", "digital/spi/rtl/inc"}
+	if !reflect.DeepEqual(incdirs, expectedDirs) {
+		t.Errorf("Expected incdirs %v, got %v", expectedDirs, incdirs)
+	}
+
+	defines := InheritedDefines(top)
+	expectedDefines := []Define{{Name: "WIDTH", Value: "16"}, {Name: "ASYNC_RESET"}}
+	if !reflect.DeepEqual(defines, expectedDefines) {
+		t.Errorf("Expected defines %v, got %v", expectedDefines, defines)
+	}
+
+	if got := InheritedDefines(lib); !reflect.DeepEqual(got, lib.Defines) {
+		t.Errorf("Dependency defines must not change: %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package component
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// InfoFile is the metadata file in the root of a component
+const InfoFile = "component.info"
+
+// Maturity tells how far a component has been verified
+type Maturity string
+
+const (
+	MaturityExperimental  Maturity = "experimental"
+	MaturityQualified     Maturity = "qualified"
+	MaturitySiliconProven Maturity = "silicon-proven"
+)
+
+// ParseMaturity parses a maturity level; spaces and underscores may be
+// used instead of the hyphen
+func ParseMaturity(s string) (Maturity, error) {
+	normalized := strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(s)))
+	switch m := Maturity(normalized); m {
+	case MaturityExperimental, MaturityQualified, MaturitySiliconProven:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown maturity %q (expected experimental, qualified or silicon-proven)", s)
+}
+
+// Info is the metadata of a component, from its component.info file or a
+// meta block in its depend.config
+type Info struct {
+	Description string
+	Owner       string
+	Maturity    Maturity
+	Nodes       []string // Supported process nodes, e.g. sky130
+	License     string
+}
+
+// IsEmpty reports whether no metadata is set
+func (i Info) IsEmpty() bool {
+	return i.Description == "" && i.Owner == "" && i.Maturity == "" && len(i.Nodes) == 0 && i.License == ""
+}
+
+// Update sets the fields that are set in other
+func (i *Info) Update(other Info) {
+	if other.Description != "" {
+		i.Description = other.Description
+	}
+	if other.Owner != "" {
+		i.Owner = other.Owner
+	}
+	if other.Maturity != "" {
+		i.Maturity = other.Maturity
+	}
+	if len(other.Nodes) > 0 {
+		i.Nodes = other.Nodes
+	}
+	if other.License != "" {
+		i.License = other.License
+	}
+}
+
+// ParseInfo parses the content of a component.info file. Each line holds a
+// "key: value" pair; empty lines and lines starting with # are skipped and
+// unknown keys are ignored. Nodes are separated by commas or spaces:
+//
+//	description: SPI master with configurable CPOL/CPHA
+//	owner: jdoe
+//	maturity: silicon-proven
+//	nodes: sky130, gf180mcu
+//	license: Apache-2.0
+func ParseInfo(content string) (*Info, error) {
+	info := &Info{}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", lineNum)
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "description":
+			info.Description = value
+		case "owner":
+			info.Owner = value
+		case "maturity":
+			maturity, err := ParseMaturity(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			info.Maturity = maturity
+		case "nodes":
+			info.Nodes = strings.FieldsFunc(value, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t'
+			})
+		case "license":
+			info.License = value
+		}
+	}
+
+	return info, scanner.Err()
+}
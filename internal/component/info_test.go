@@ -0,0 +1,53 @@
+package component
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInfo(t *testing.T) {
+	content := `# SPI master
+Description: SPI master with configurable CPOL/CPHA
+owner:   jdoe
+maturity: Silicon Proven
+nodes: sky130, gf180mcu  ihp-sg13g2
+license: Apache-2.0
+
+homepage: http://example.com
+`
+	info, err := ParseInfo(content)
+	if err != nil {
+		t.Fatalf("ParseInfo failed: %v", err)
+	}
+
+	expected := &Info{
+		Description: "SPI master with configurable CPOL/CPHA",
+		Owner:       "jdoe",
+		Maturity:    MaturitySiliconProven,
+		Nodes:       []string{"sky130", "gf180mcu", "ihp-sg13g2"},
+		License:     "Apache-2.0",
+	}
+	if !reflect.DeepEqual(info, expected) {
+		t.Errorf("Unexpected info:\n got %+v\nwant %+v", info, expected)
+	}
+
+	if _, err := ParseInfo("description SPI master"); err == nil {
+		t.Error("Expected error for line without colon")
+	}
+	if _, err := ParseInfo("maturity: tapeout-ready"); err == nil {
+		t.Error("Expected error for unknown maturity")
+	}
+}
+
+func TestInfoUpdate(t *testing.T) {
+	info := Info{Description: "From depend.config", Owner: "jdoe", Nodes: []string{"sky130"}}
+	info.Update(Info{Description: "From component.info", Maturity: MaturityQualified})
+
+	expected := Info{Description: "From component.info", Owner: "jdoe", Maturity: MaturityQualified, Nodes: []string{"sky130"}}
+	if !reflect.DeepEqual(info, expected) {
+		t.Errorf("Unexpected info after update: %+v", info)
+	}
+	if !(Info{}).IsEmpty() || info.IsEmpty() {
+		t.Error("Unexpected IsEmpty result")
+	}
+}
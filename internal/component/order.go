@@ -0,0 +1,41 @@
+package component
+
+import "sort"
+
+// DependencyOrder returns the given components and all of their transitive
+// dependencies so that every component comes after the components it depends
+// on. Siblings are visited in name order, making the result deterministic.
+// Circular dependencies are broken at the first component seen twice.
+func DependencyOrder(roots []*Component) []*Component {
+	var ordered []*Component
+	visited := make(map[string]bool)
+
+	var visit func(comp *Component)
+	visit = func(comp *Component) {
+		if visited[comp.Name] {
+			return
+		}
+		visited[comp.Name] = true
+
+		for _, dep := range sortedByName(comp.Dependencies) {
+			visit(dep)
+		}
+		ordered = append(ordered, comp)
+	}
+
+	for _, comp := range sortedByName(roots) {
+		visit(comp)
+	}
+
+	return ordered
+}
+
+// sortedByName returns a copy of comps sorted by component name
+func sortedByName(comps []*Component) []*Component {
+	sorted := make([]*Component, len(comps))
+	copy(sorted, comps)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
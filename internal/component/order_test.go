@@ -0,0 +1,26 @@
+package component
+
+import "testing"
+
+func TestDependencyOrder(t *testing.T) {
+	syncFF := &Component{Name: "digital/sync_ff"}
+	bias := &Component{Name: "analog/bias"}
+	spi := &Component{Name: "digital/spi_master", Dependencies: []*Component{syncFF}}
+	top := &Component{Name: "digital/top", Dependencies: []*Component{spi, bias, syncFF}}
+	setup := &Component{Name: "setup/analog"}
+
+	// Circular dependency must not loop forever
+	syncFF.Dependencies = []*Component{top}
+
+	ordered := DependencyOrder([]*Component{top, setup})
+
+	expected := []string{"analog/bias", "digital/sync_ff", "digital/spi_master", "digital/top", "setup/analog"}
+	if len(ordered) != len(expected) {
+		t.Fatalf("Expected %d components, got %d", len(expected), len(ordered))
+	}
+	for i, comp := range ordered {
+		if comp.Name != expected[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, expected[i], comp.Name)
+		}
+	}
+}
@@ -13,6 +13,9 @@ const (
 	TypeTools   ComponentType = "tools" // For software tools in Git
 )
 
+// RepositoryTypes are the component types stored in the SVN repository
+var RepositoryTypes = []ComponentType{TypeAnalog, TypeDigital, TypeSetup, TypeProcess}
+
 // Component represents a design component or software tool
 type Component struct {
 	Name   string        // Component identifier (e.g., "digital/my_module")
@@ -24,16 +27,86 @@ type Component struct {
 	// Dependencies
 	Dependencies []*Component
 
+	// Hooks declared in the component's depend.config
+	Hooks []Hook
+
+	// Environment variables exported by the component (env directives in depend.config)
+	Env []EnvVar
+
+	// HDL source layout (hdl_dirs/hdl_exclude directives in depend.config)
+	HDLDirs    []string // Directories searched for HDL files; the component root if empty
+	HDLExclude []string // Ignore patterns, same syntax as .icwignore
+
+	// Flow-specific file selection (include/exclude directives in depend.config)
+	FlowRules []FlowRule
+
+	// HDL compile settings (incdir/define/library/top directives in depend.config).
+	// Include directories and defines also apply to components using this one.
+	Incdirs []string // Include directories relative to the component root
+	Defines []Define
+	Library string // VHDL library the component compiles into; "work" if empty
+	Top     string // Top-level module or entity of the component
+
+	// Metadata from component.info or the meta block in depend.config
+	Info Info
+
 	// For tracking conflicts
 	Resolved   bool
 	DeclaredBy string // Name of component that declared this dependency (for conflict reporting)
 }
 
+// Hook events
+const (
+	HookPostCheckout = "post_checkout" // After the first checkout of a component
+	HookPostUpdate   = "post_update"   // After every checkout, update or switch
+)
+
+// Hook is a shell command run after a component is checked out or updated
+type Hook struct {
+	Event   string // HookPostCheckout or HookPostUpdate
+	Command string // Shell command, run in the component directory
+	Source  string // Where the hook was declared ("workspace.config" or a component name)
+}
+
+// EnvVar is an environment variable exported by a component for 'icw env'
+type EnvVar struct {
+	Name  string
+	Value string // May reference $ICW_ROOT, $ICW_COMPONENT_PATH and earlier variables
+}
+
+// FlowRule includes or excludes HDL files in one build flow
+type FlowRule struct {
+	Include bool   // true for include, false for exclude
+	Flow    string // Flow name, e.g. "synthesis" or "gate-sim"
+	Pattern string // File pattern relative to the component root
+}
+
+// Define is a preprocessor define for the component's HDL; Value may be empty
+type Define struct {
+	Name  string
+	Value string
+}
+
+// String returns the define as NAME or NAME=VALUE
+func (d Define) String() string {
+	if d.Value == "" {
+		return d.Name
+	}
+	return d.Name + "=" + d.Value
+}
+
+// IsTopLevel reports whether the component is declared in workspace.config
+func (c *Component) IsTopLevel() bool {
+	return strings.Contains(c.DeclaredBy, "workspace.config")
+}
+
 // Workspace represents the entire workspace configuration
 type Workspace struct {
 	Root       string                // Workspace root directory
 	Components map[string]*Component // Components indexed by name
 	Config     string                // Path to workspace.config
+
+	order []string // Component names in the order they were first added
 }
 
 // NewWorkspace creates a new workspace instance
@@ -72,9 +145,22 @@ func (w *Workspace) AddComponent(comp *Component) error {
 
 	// Add new component
 	w.Components[comp.Name] = comp
+	w.order = append(w.order, comp.Name)
 	return nil
 }
 
+// Ordered returns the workspace components in declaration order
+// (workspace.config first, then dependencies in the order they were resolved)
+func (w *Workspace) Ordered() []*Component {
+	comps := make([]*Component, 0, len(w.order))
+	for _, name := range w.order {
+		if comp, ok := w.Components[name]; ok {
+			comps = append(comps, comp)
+		}
+	}
+	return comps
+}
+
 // GetComponent retrieves a component by name
 func (w *Workspace) GetComponent(name string) (*Component, bool) {
 	comp, ok := w.Components[name]
@@ -112,3 +112,25 @@ func TestBranchConflictErrorMessage(t *testing.T) {
 		t.Error("Error message should contain new source")
 	}
 }
+
+func TestWorkspaceOrdered(t *testing.T) {
+	ws := NewWorkspace("/tmp/test")
+
+	names := []string{"setup/analog", "digital/top", "analog/bias"}
+	for _, name := range names {
+		ws.AddComponent(&Component{Name: name, Path: name, Branch: "trunk"})
+	}
+
+	// Re-adding an existing component must not change the order
+	ws.AddComponent(&Component{Name: "setup/analog", Path: "setup/analog", Branch: "trunk"})
+
+	ordered := ws.Ordered()
+	if len(ordered) != len(names) {
+		t.Fatalf("Expected %d components, got %d", len(names), len(ordered))
+	}
+	for i, comp := range ordered {
+		if comp.Name != names[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, names[i], comp.Name)
+		}
+	}
+}
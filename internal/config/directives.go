@@ -0,0 +1,202 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/hdl"
+	"github.com/jakobsen/icw/internal/target"
+)
+
+var (
+	// hook <type> <event> "command" (workspace.config)
+	workspaceHookPattern = regexp.MustCompile(`^hook\s+(\w+)\s+(\w+)\s+"(.*)"\s*$`)
+
+	// output <target> "path" [flow] (workspace.config)
+	outputPattern = regexp.MustCompile(`^output\s+([\w-]+)\s+"([^"]+)"(?:\s+([\w-]+))?\s*$`)
+
+	// hook <event> "command" (depend.config)
+	componentHookPattern = regexp.MustCompile(`^hook\s+(\w+)\s+"(.*)"\s*$`)
+
+	// env NAME "value" (depend.config)
+	envPattern = regexp.MustCompile(`^env\s+([A-Za-z_][A-Za-z0-9_]*)\s+"(.*)"\s*$`)
+
+	// hdl_dirs "dir" ..., hdl_exclude "pattern" ... and incdir "dir" ... (depend.config)
+	hdlLayoutPattern = regexp.MustCompile(`^(hdl_dirs|hdl_exclude|incdir)((?:\s+"[^"]*")+)\s*$`)
+	quotedPattern    = regexp.MustCompile(`"([^"]*)"`)
+
+	// define NAME ["value"], library "name" and top "unit" (depend.config)
+	definePattern  = regexp.MustCompile(`^define\s+([A-Za-z_]\w*)(?:\s+"(.*)")?\s*$`)
+	libraryPattern = regexp.MustCompile(`^library\s+"([A-Za-z]\w*)"\s*$`)
+	topPattern     = regexp.MustCompile(`^top\s+"([A-Za-z_]\w*)"\s*$`)
+
+	// include <flow> "pattern" and exclude <flow> "pattern" (depend.config)
+	flowRulePattern = regexp.MustCompile(`^(include|exclude)\s+([\w-]+)\s+"([^"]+)"\s*$`)
+)
+
+// parseWorkspaceDirective parses workspace.config directives other than
+// repository settings and component declarations.
+// Returns true if the line was a directive.
+func (p *Parser) parseWorkspaceDirective(line string) (bool, error) {
+	if matches := workspaceHookPattern.FindStringSubmatch(line); matches != nil {
+		compType := component.ComponentType(matches[1])
+		if !isKnownType(compType) {
+			return true, fmt.Errorf("unknown component type in hook: %s", matches[1])
+		}
+		if !isKnownHookEvent(matches[2]) {
+			return true, fmt.Errorf("unknown hook event: %s", matches[2])
+		}
+		p.TypeHooks[compType] = append(p.TypeHooks[compType], component.Hook{
+			Event:   matches[2],
+			Command: unquote(matches[3]),
+			Source:  "workspace.config",
+		})
+		return true, nil
+	}
+
+	if isDirective(line, "hook") {
+		return true, fmt.Errorf("invalid hook syntax, expected: hook <type> <event> \"command\"")
+	}
+
+	if matches := outputPattern.FindStringSubmatch(line); matches != nil {
+		if !target.IsSingleFile(matches[1]) {
+			return true, fmt.Errorf("unsupported output target: %s", matches[1])
+		}
+		output := Output{Target: matches[1], Path: matches[2]}
+		if matches[3] != "" {
+			flow, err := hdl.ParseFlow(matches[3])
+			if err != nil {
+				return true, err
+			}
+			output.Flow = flow
+		}
+		p.Outputs = append(p.Outputs, output)
+		return true, nil
+	}
+
+	if isDirective(line, "output") {
+		return true, fmt.Errorf("invalid output syntax, expected: output <target> \"path\" [flow]")
+	}
+
+	return false, nil
+}
+
+// parseComponentDirective parses depend.config directives that describe the
+// component itself rather than its dependencies.
+// Returns true if the line was a directive.
+func (p *Parser) parseComponentDirective(comp *component.Component, line string) (bool, error) {
+	if matches := componentHookPattern.FindStringSubmatch(line); matches != nil {
+		if !isKnownHookEvent(matches[1]) {
+			return true, fmt.Errorf("unknown hook event: %s", matches[1])
+		}
+		comp.Hooks = append(comp.Hooks, component.Hook{
+			Event:   matches[1],
+			Command: unquote(matches[2]),
+			Source:  comp.Name,
+		})
+		return true, nil
+	}
+
+	if isDirective(line, "hook") {
+		return true, fmt.Errorf("invalid hook syntax, expected: hook <event> \"command\"")
+	}
+
+	if matches := envPattern.FindStringSubmatch(line); matches != nil {
+		comp.Env = append(comp.Env, component.EnvVar{
+			Name:  matches[1],
+			Value: unquote(matches[2]),
+		})
+		return true, nil
+	}
+
+	if isDirective(line, "env") {
+		return true, fmt.Errorf("invalid env syntax, expected: env NAME \"value\"")
+	}
+
+	if matches := hdlLayoutPattern.FindStringSubmatch(line); matches != nil {
+		var values []string
+		for _, quoted := range quotedPattern.FindAllStringSubmatch(matches[2], -1) {
+			values = append(values, quoted[1])
+		}
+		switch matches[1] {
+		case "hdl_dirs":
+			comp.HDLDirs = append(comp.HDLDirs, values...)
+		case "hdl_exclude":
+			comp.HDLExclude = append(comp.HDLExclude, values...)
+		case "incdir":
+			comp.Incdirs = append(comp.Incdirs, values...)
+		}
+		return true, nil
+	}
+
+	if isDirective(line, "hdl_dirs") || isDirective(line, "hdl_exclude") || isDirective(line, "incdir") {
+		return true, fmt.Errorf("invalid syntax, expected: %s \"value\" ...", strings.Fields(line)[0])
+	}
+
+	if matches := definePattern.FindStringSubmatch(line); matches != nil {
+		comp.Defines = append(comp.Defines, component.Define{Name: matches[1], Value: unquote(matches[2])})
+		return true, nil
+	}
+	if isDirective(line, "define") {
+		return true, fmt.Errorf("invalid define syntax, expected: define NAME [\"value\"]")
+	}
+
+	if matches := libraryPattern.FindStringSubmatch(line); matches != nil {
+		comp.Library = strings.ToLower(matches[1])
+		return true, nil
+	}
+	if isDirective(line, "library") {
+		return true, fmt.Errorf("invalid library syntax, expected: library \"name\"")
+	}
+
+	if matches := topPattern.FindStringSubmatch(line); matches != nil {
+		comp.Top = matches[1]
+		return true, nil
+	}
+	if isDirective(line, "top") {
+		return true, fmt.Errorf("invalid top syntax, expected: top \"unit\"")
+	}
+
+	if matches := flowRulePattern.FindStringSubmatch(line); matches != nil {
+		flow, err := hdl.ParseFlow(matches[2])
+		if err != nil {
+			return true, err
+		}
+		comp.FlowRules = append(comp.FlowRules, component.FlowRule{
+			Include: matches[1] == "include",
+			Flow:    string(flow),
+			Pattern: matches[3],
+		})
+		return true, nil
+	}
+
+	if isDirective(line, "include") || isDirective(line, "exclude") {
+		return true, fmt.Errorf("invalid syntax, expected: %s <flow> \"pattern\"", strings.Fields(line)[0])
+	}
+
+	return false, nil
+}
+
+// unquote resolves escaped double quotes in a quoted directive argument
+func unquote(value string) string {
+	return strings.ReplaceAll(value, `\"`, `"`)
+}
+
+// isDirective reports whether line starts with the given keyword
+func isDirective(line, keyword string) bool {
+	return regexp.MustCompile(`^` + keyword + `\b`).MatchString(line)
+}
+
+func isKnownHookEvent(event string) bool {
+	return event == component.HookPostCheckout || event == component.HookPostUpdate
+}
+
+func isKnownType(compType component.ComponentType) bool {
+	switch compType {
+	case component.TypeAnalog, component.TypeDigital, component.TypeSetup, component.TypeProcess, component.TypeTools:
+		return true
+	}
+	return false
+}
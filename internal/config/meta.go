@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// meta { (depend.config)
+var metaStartPattern = regexp.MustCompile(`^meta\s*\{$`)
+
+// metaBlock collects the lines of a meta block in depend.config, which holds
+// the same "key: value" lines as a component.info file:
+//
+//	meta {
+//	    owner: jdoe
+//	    maturity: qualified
+//	}
+type metaBlock struct {
+	lines  []string // Indexed by line number - 1; other lines are empty
+	inside bool
+	start  int // Line number of "meta {"; 0 if there is no block
+}
+
+// add consumes line if it belongs to a meta block.
+// Returns true if the line was part of the block.
+func (m *metaBlock) add(line string, lineNum int) (bool, error) {
+	if m.inside {
+		if line == "}" {
+			m.inside = false
+		} else {
+			// Pad so that errors report the line number in depend.config
+			for len(m.lines) < lineNum-1 {
+				m.lines = append(m.lines, "")
+			}
+			m.lines = append(m.lines, line)
+		}
+		return true, nil
+	}
+
+	if metaStartPattern.MatchString(line) {
+		if m.start != 0 {
+			return true, fmt.Errorf("duplicate meta block, first one at line %d", m.start)
+		}
+		m.inside = true
+		m.start = lineNum
+		return true, nil
+	}
+	if isDirective(line, "meta") {
+		return true, fmt.Errorf("invalid meta syntax, expected: meta {")
+	}
+	return false, nil
+}
+
+// info parses the collected block
+func (m *metaBlock) info() (component.Info, error) {
+	if m.inside {
+		return component.Info{}, fmt.Errorf("line %d: meta block is not closed", m.start)
+	}
+	info, err := component.ParseInfo(strings.Join(m.lines, "\n"))
+	if err != nil {
+		return component.Info{}, fmt.Errorf("meta block: %w", err)
+	}
+	return *info, nil
+}
+
+// ParseMeta returns the metadata in the meta block of depend.config content,
+// e.g. as fetched from the repository
+func ParseMeta(content string) (component.Info, error) {
+	meta := &metaBlock{}
+	for i, line := range strings.Split(content, "\n") {
+		if _, err := meta.add(strings.TrimSpace(line), i+1); err != nil {
+			return component.Info{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return meta.info()
+}
+
+// LoadInfo reads a component.info file into comp.Info, overriding fields set
+// by the meta block of depend.config. A missing file is not an error.
+func LoadInfo(comp *component.Component, path string) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	info, err := component.ParseInfo(string(content))
+	if err != nil {
+		return fmt.Errorf("%s: %w", component.InfoFile, err)
+	}
+	comp.Info.Update(*info)
+	return nil
+}
@@ -3,11 +3,13 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
 
 	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/hdl"
 )
 
 // Parser handles parsing of workspace.config and depend.config files
@@ -16,6 +18,22 @@ type Parser struct {
 	Repo      string // Repository name from config file
 	SvnURL    string // SVN URL from config file
 	processed map[string]bool // Track processed components to avoid infinite loops
+
+	// Hooks declared in workspace.config for all components of a type
+	TypeHooks map[component.ComponentType][]component.Hook
+
+	// Generated files declared in workspace.config, kept up to date by icw watch
+	Outputs []Output
+
+	// Problems in depend.config files that did not stop parsing
+	warnings []string
+}
+
+// Output is a project file generated from the workspace HDL
+type Output struct {
+	Target string   // Export target, e.g. "filelist" or "make"
+	Path   string   // Output file, relative to the workspace root
+	Flow   hdl.Flow // Flow to select files for; empty for the target's default
 }
 
 // NewParser creates a new config parser
@@ -23,6 +41,7 @@ func NewParser(ws *component.Workspace) *Parser {
 	return &Parser{
 		workspace: ws,
 		processed: make(map[string]bool),
+		TypeHooks: make(map[component.ComponentType][]component.Hook),
 	}
 }
 
@@ -51,6 +70,15 @@ func (p *Parser) ParseWorkspaceConfig(path string) error {
 			continue
 		}
 
+		// Check for workspace directives (type-wide hooks)
+		handled, err := p.parseWorkspaceDirective(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if handled {
+			continue
+		}
+
 		// Parse component declaration
 		comp, err := p.parseComponentLine(line)
 		if err != nil {
@@ -220,46 +248,105 @@ func (p *Parser) ParseDependConfig(parent *component.Component, dependConfigPath
 	defer file.Close()
 
 	var dependencies []*component.Component
-	scanner := bufio.NewScanner(file)
+	err = p.parseDepend(parent, file, func(comp *component.Component) error {
+		// Set the DeclaredBy field to track where this dependency came from
+		comp.DeclaredBy = parent.Name
+
+		// Add component to workspace (with conflict detection)
+		if err := p.workspace.AddComponent(comp); err != nil {
+			// Check if it's a branch conflict
+			if conflictErr, ok := err.(*component.BranchConflictError); ok {
+				return fmt.Errorf("dependency conflict: %w", conflictErr)
+			}
+			return err
+		}
+
+		// Use the workspace's instance so all parents share one component
+		if existing, ok := p.workspace.GetComponent(comp.Name); ok {
+			comp = existing
+		}
+
+		// Add to parent's dependencies
+		parent.Dependencies = append(parent.Dependencies, comp)
+		dependencies = append(dependencies, comp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+// parseDepend reads depend.config content: directives and the meta block are
+// applied to parent, and each declared component is passed to use in order
+func (p *Parser) parseDepend(parent *component.Component, r io.Reader, use func(*component.Component) error) error {
+	scanner := bufio.NewScanner(r)
 	lineNum := 0
+	meta := &metaBlock{}
 
 	for scanner.Scan() {
 		lineNum++
 		line := strings.TrimSpace(scanner.Text())
 
+		// Collect the lines of a meta { ... } block. Metadata is
+		// informational, so a broken block does not stop parsing.
+		inMeta, err := meta.add(line, lineNum)
+		if err != nil {
+			p.warn(parent, fmt.Errorf("line %d: %w", lineNum, err))
+		}
+		if inMeta {
+			continue
+		}
+
 		// Skip empty lines and comments
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
 
+		// Check for component directives (hooks, env, HDL layout)
+		handled, err := p.parseComponentDirective(parent, line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if handled {
+			continue
+		}
+
 		// Parse component declaration (same syntax as workspace.config)
 		comp, err := p.parseComponentLine(line)
 		if err != nil {
-			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			return fmt.Errorf("line %d: %w", lineNum, err)
 		}
 
 		if comp != nil {
-			// Set the DeclaredBy field to track where this dependency came from
-			comp.DeclaredBy = parent.Name
-
-			// Add component to workspace (with conflict detection)
-			if err := p.workspace.AddComponent(comp); err != nil {
-				// Check if it's a branch conflict
-				if conflictErr, ok := err.(*component.BranchConflictError); ok {
-					return nil, fmt.Errorf("dependency conflict: %w", conflictErr)
-				}
-				return nil, err
+			if err := use(comp); err != nil {
+				return err
 			}
-
-			// Add to parent's dependencies
-			parent.Dependencies = append(parent.Dependencies, comp)
-			dependencies = append(dependencies, comp)
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return err
 	}
 
-	return dependencies, nil
+	info, err := meta.info()
+	if err != nil {
+		p.warn(parent, err)
+		return nil
+	}
+	parent.Info.Update(info)
+	return nil
+}
+
+// warn records a problem in the depend.config of comp
+func (p *Parser) warn(comp *component.Component, err error) {
+	p.warnings = append(p.warnings, fmt.Sprintf("%s: depend.config %v", comp.Name, err))
+}
+
+// TakeWarnings returns the problems recorded since the last call
+func (p *Parser) TakeWarnings() []string {
+	warnings := p.warnings
+	p.warnings = nil
+	return warnings
 }
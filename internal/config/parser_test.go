@@ -3,9 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/hdl"
 )
 
 func TestParseDependConfig(t *testing.T) {
@@ -214,3 +216,343 @@ func findInString(str, substr string) bool {
 	}
 	return false
 }
+
+func TestParseHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	workspaceConfig := filepath.Join(tmpDir, "workspace.config")
+	workspaceContent := `set repo "icworks"
+hook setup post_update "./setup"
+hook digital post_checkout "make -C sim init"
+use component("digital/top", "digital", "trunk")
+`
+	if err := os.WriteFile(workspaceConfig, []byte(workspaceContent), 0644); err != nil {
+		t.Fatalf("Failed to create workspace.config: %v", err)
+	}
+
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+	if err := parser.ParseWorkspaceConfig(workspaceConfig); err != nil {
+		t.Fatalf("Failed to parse workspace.config: %v", err)
+	}
+
+	if hooks := parser.TypeHooks[component.TypeSetup]; len(hooks) != 1 || hooks[0].Command != "./setup" || hooks[0].Event != component.HookPostUpdate {
+		t.Errorf("Unexpected setup hooks: %+v", hooks)
+	}
+	if hooks := parser.TypeHooks[component.TypeDigital]; len(hooks) != 1 || hooks[0].Event != component.HookPostCheckout {
+		t.Errorf("Unexpected digital hooks: %+v", hooks)
+	}
+
+	top, _ := ws.GetComponent("digital/top")
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `use component("digital/spi_master", "digital", "trunk")
+hook post_update "python3 gen_regs.py --use \"regs.yaml\""
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	deps, err := parser.ParseDependConfig(top, dependConfigPath)
+	if err != nil {
+		t.Fatalf("Failed to parse depend.config: %v", err)
+	}
+	if len(deps) != 1 {
+		t.Errorf("Expected 1 dependency, got %d", len(deps))
+	}
+	if len(top.Hooks) != 1 {
+		t.Fatalf("Expected 1 hook, got %d", len(top.Hooks))
+	}
+	if top.Hooks[0].Command != `python3 gen_regs.py --use "regs.yaml"` || top.Hooks[0].Source != "digital/top" {
+		t.Errorf("Unexpected hook: %+v", top.Hooks[0])
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	workspaceConfig := filepath.Join(tmpDir, "workspace.config")
+	workspaceContent := `output filelist "build/rtl.f"
+output make "build/syn.mk" synth
+use component("digital/top", "digital", "trunk")
+`
+	if err := os.WriteFile(workspaceConfig, []byte(workspaceContent), 0644); err != nil {
+		t.Fatalf("Failed to create workspace.config: %v", err)
+	}
+
+	parser := NewParser(component.NewWorkspace(tmpDir))
+	if err := parser.ParseWorkspaceConfig(workspaceConfig); err != nil {
+		t.Fatalf("Failed to parse workspace.config: %v", err)
+	}
+
+	expected := []Output{
+		{Target: "filelist", Path: "build/rtl.f"},
+		{Target: "make", Path: "build/syn.mk", Flow: hdl.FlowSynthesis},
+	}
+	if len(parser.Outputs) != 2 || parser.Outputs[0] != expected[0] || parser.Outputs[1] != expected[1] {
+		t.Errorf("Unexpected outputs: %+v", parser.Outputs)
+	}
+
+	for _, invalid := range []string{`output fusesoc "cores"`, `output quartus "x.qsf"`, `output make "x.mk" layout`, `output make x.mk`} {
+		os.WriteFile(workspaceConfig, []byte(invalid), 0644)
+		parser := NewParser(component.NewWorkspace(tmpDir))
+		if err := parser.ParseWorkspaceConfig(workspaceConfig); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestParseHooksInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+
+	parent := &component.Component{Name: "digital/top", Path: "digital/top", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	os.WriteFile(dependConfigPath, []byte(`hook pre_commit "lint.sh"`), 0644)
+
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err == nil {
+		t.Error("Expected error for unknown hook event")
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+
+	parent := &component.Component{Name: "process/sky130A", Path: "process/sky130A", Type: component.TypeProcess, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `env PDK_ROOT "$ICW_COMPONENT_PATH/pdk"
+env PDK "sky130A"
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err != nil {
+		t.Fatalf("Failed to parse depend.config: %v", err)
+	}
+
+	expected := []component.EnvVar{
+		{Name: "PDK_ROOT", Value: "$ICW_COMPONENT_PATH/pdk"},
+		{Name: "PDK", Value: "sky130A"},
+	}
+	if len(parent.Env) != len(expected) {
+		t.Fatalf("Expected %d variables, got %d", len(expected), len(parent.Env))
+	}
+	for i, v := range expected {
+		if parent.Env[i] != v {
+			t.Errorf("Env[%d] = %+v, expected %+v", i, parent.Env[i], v)
+		}
+	}
+
+	// Invalid variable names are rejected
+	parser = NewParser(ws)
+	os.WriteFile(dependConfigPath, []byte(`env 1PDK "x"`), 0644)
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err == nil {
+		t.Error("Expected error for invalid variable name")
+	}
+}
+
+func TestParseHDLLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+
+	parent := &component.Component{Name: "digital/top", Path: "digital/top", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `hdl_dirs "rtl" "tb"
+hdl_exclude "*_old.v"
+hdl_exclude "rtl/unused/" "!rtl/unused/keep.v"
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err != nil {
+		t.Fatalf("Failed to parse depend.config: %v", err)
+	}
+
+	if strings.Join(parent.HDLDirs, ",") != "rtl,tb" {
+		t.Errorf("Unexpected hdl_dirs: %v", parent.HDLDirs)
+	}
+	if strings.Join(parent.HDLExclude, ",") != "*_old.v,rtl/unused/,!rtl/unused/keep.v" {
+		t.Errorf("Unexpected hdl_exclude: %v", parent.HDLExclude)
+	}
+
+	parser = NewParser(ws)
+	os.WriteFile(dependConfigPath, []byte(`hdl_dirs rtl`), 0644)
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err == nil {
+		t.Error("Expected error for unquoted hdl_dirs")
+	}
+}
+
+func TestParseFlowRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+
+	parent := &component.Component{Name: "digital/top", Path: "digital/top", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `exclude synthesis "dig_top_cp3_gate.v"
+include post-synth-sim "dig_top_cp3_gate.v"
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err != nil {
+		t.Fatalf("Failed to parse depend.config: %v", err)
+	}
+
+	expected := []component.FlowRule{
+		{Include: false, Flow: "synthesis", Pattern: "dig_top_cp3_gate.v"},
+		{Include: true, Flow: "gate-sim", Pattern: "dig_top_cp3_gate.v"},
+	}
+	if len(parent.FlowRules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %d", len(expected), len(parent.FlowRules))
+	}
+	for i, rule := range expected {
+		if parent.FlowRules[i] != rule {
+			t.Errorf("FlowRules[%d] = %+v, expected %+v", i, parent.FlowRules[i], rule)
+		}
+	}
+
+	parser = NewParser(ws)
+	os.WriteFile(dependConfigPath, []byte(`exclude layout "top.v"`), 0644)
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err == nil {
+		t.Error("Expected error for unknown flow")
+	}
+}
+
+func TestParseHDLSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+
+	parent := &component.Component{Name: "digital/uart", Path: "digital/uart", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `incdir "include" "rtl/inc"
+define SIMULATION
+define BAUD_DIV "16'd434"
+library "UART_LIB"
+top "uart_top"
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	if _, err := parser.ParseDependConfig(parent, dependConfigPath); err != nil {
+		t.Fatalf("Failed to parse depend.config: %v", err)
+	}
+
+	if strings.Join(parent.Incdirs, ",") != "include,rtl/inc" {
+		t.Errorf("Unexpected incdirs: %v", parent.Incdirs)
+	}
+	expected := []component.Define{{Name: "SIMULATION"}, {Name: "BAUD_DIV", Value: "16'd434"}}
+	if len(parent.Defines) != 2 || parent.Defines[0] != expected[0] || parent.Defines[1] != expected[1] {
+		t.Errorf("Unexpected defines: %+v", parent.Defines)
+	}
+	if parent.Library != "uart_lib" {
+		t.Errorf("Expected library uart_lib, got %q", parent.Library)
+	}
+	if parent.Top != "uart_top" {
+		t.Errorf("Expected top uart_top, got %q", parent.Top)
+	}
+
+	for _, invalid := range []string{`define 8BIT`, `library uart_lib`, `top "a" "b"`} {
+		parser = NewParser(ws)
+		os.WriteFile(dependConfigPath, []byte(invalid), 0644)
+		if _, err := parser.ParseDependConfig(parent, dependConfigPath); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestParseMeta(t *testing.T) {
+	tmpDir := t.TempDir()
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+
+	parent := &component.Component{Name: "digital/fifo", Path: "digital/fifo", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `meta {
+    description: Reusable FIFO with use of gray-coded pointers
+    owner: jdoe
+    maturity: qualified
+    nodes: sky130, gf180mcu
+}
+use component("digital/sync_ff", "digital", "trunk")
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	deps, err := parser.ParseDependConfig(parent, dependConfigPath)
+	if err != nil {
+		t.Fatalf("Failed to parse depend.config: %v", err)
+	}
+	if len(deps) != 1 {
+		t.Errorf("Expected 1 dependency, got %d", len(deps))
+	}
+	if parent.Info.Owner != "jdoe" || parent.Info.Maturity != component.MaturityQualified || len(parent.Info.Nodes) != 2 {
+		t.Errorf("Unexpected info: %+v", parent.Info)
+	}
+
+	// component.info overrides the meta block
+	infoPath := filepath.Join(tmpDir, component.InfoFile)
+	os.WriteFile(infoPath, []byte("owner: mhansen\nlicense: Apache-2.0\n"), 0644)
+	if err := LoadInfo(parent, infoPath); err != nil {
+		t.Fatalf("LoadInfo failed: %v", err)
+	}
+	if parent.Info.Owner != "mhansen" || parent.Info.License != "Apache-2.0" || parent.Info.Maturity != component.MaturityQualified {
+		t.Errorf("Unexpected info after component.info: %+v", parent.Info)
+	}
+
+	info, err := ParseMeta(dependContent)
+	if err != nil || info.Description != "Reusable FIFO with use of gray-coded pointers" {
+		t.Errorf("ParseMeta = %+v, %v", info, err)
+	}
+	if info, err := ParseMeta(`use component("digital/sync_ff", "digital", "trunk")`); err != nil || !info.IsEmpty() {
+		t.Errorf("Expected empty info without meta block, got %+v, %v", info, err)
+	}
+	for _, invalid := range []string{"meta {\nowner: jdoe\n", "meta {\n}\nmeta {\n}\n", "meta owner: jdoe", "meta {\nmaturity: done\n}"} {
+		if _, err := ParseMeta(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestParseDependConfigBrokenMeta(t *testing.T) {
+	tmpDir := t.TempDir()
+	parser := NewParser(component.NewWorkspace(tmpDir))
+
+	parent := &component.Component{Name: "digital/fifo", Path: "digital/fifo", Type: component.TypeDigital, Branch: "trunk", VCS: "svn"}
+	dependConfigPath := filepath.Join(tmpDir, "depend.config")
+	dependContent := `# FIFO
+meta {
+    owner: jdoe
+    maturity: done
+}
+use component("digital/sync_ff", "digital", "trunk")
+`
+	if err := os.WriteFile(dependConfigPath, []byte(dependContent), 0644); err != nil {
+		t.Fatalf("Failed to create depend.config: %v", err)
+	}
+
+	// The metadata is informational, the dependencies are still resolved
+	deps, err := parser.ParseDependConfig(parent, dependConfigPath)
+	if err != nil {
+		t.Fatalf("Broken meta block must not fail parsing: %v", err)
+	}
+	if len(deps) != 1 {
+		t.Errorf("Expected 1 dependency, got %d", len(deps))
+	}
+	warnings := parser.TakeWarnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "line 4:") {
+		t.Errorf("Expected a warning for line 4, got %v", warnings)
+	}
+	if len(parser.TakeWarnings()) != 0 {
+		t.Error("TakeWarnings must clear the warnings")
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// ParseDependencies returns the components declared with use lines in
+// depend.config content, e.g. as fetched from a tag in the repository.
+// Other directives are checked but not recorded.
+func ParseDependencies(content string) ([]*component.Component, error) {
+	p := NewParser(component.NewWorkspace(""))
+
+	var deps []*component.Component
+	err := p.parseDepend(&component.Component{}, strings.NewReader(content), func(comp *component.Component) error {
+		deps = append(deps, comp)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+// PinChange is a dependency whose branch or tag differs between two
+// versions of a depend.config
+type PinChange struct {
+	Name string
+	Old  string // Branch before, empty if the dependency was added
+	New  string // Branch after, empty if the dependency was removed
+}
+
+// String describes the change, e.g. "digital/fifo: tags/v1.0 -> tags/v1.1"
+func (c PinChange) String() string {
+	switch {
+	case c.Old == "":
+		return fmt.Sprintf("%s: added at %s", c.Name, c.New)
+	case c.New == "":
+		return fmt.Sprintf("%s: removed (was %s)", c.Name, c.Old)
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Name, c.Old, c.New)
+}
+
+// DiffPins compares the dependencies of two versions of a component and
+// returns the changes sorted by name
+func DiffPins(old, new []*component.Component) []PinChange {
+	oldPins := make(map[string]string)
+	for _, dep := range old {
+		oldPins[dep.Name] = dep.Branch
+	}
+	newPins := make(map[string]string)
+	for _, dep := range new {
+		newPins[dep.Name] = dep.Branch
+	}
+
+	var changes []PinChange
+	for name, branch := range oldPins {
+		if newBranch, ok := newPins[name]; !ok || newBranch != branch {
+			changes = append(changes, PinChange{Name: name, Old: branch, New: newBranch})
+		}
+	}
+	for name, branch := range newPins {
+		if _, ok := oldPins[name]; !ok {
+			changes = append(changes, PinChange{Name: name, New: branch})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
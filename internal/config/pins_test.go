@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDependencies(t *testing.T) {
+	content := `meta {
+    owner: jdoe
+}
+# Dependencies
+hdl_dirs "rtl"
+use component("digital/fifo", "digital", "tags/v1.0")
+use component("analog/bias")
+use ref("digital/local_pkg")
+`
+	deps, err := ParseDependencies(content)
+	if err != nil {
+		t.Fatalf("ParseDependencies failed: %v", err)
+	}
+
+	var got []string
+	for _, dep := range deps {
+		got = append(got, dep.Name+"@"+dep.Branch)
+	}
+	want := []string{"digital/fifo@tags/v1.0", "analog/bias@trunk", "digital/local_pkg@local"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies = %v, want %v", got, want)
+	}
+
+	if _, err := ParseDependencies(`use component(digital/fifo)`); err == nil {
+		t.Error("Expected error for invalid use line")
+	}
+}
+
+func TestDiffPins(t *testing.T) {
+	old, _ := ParseDependencies(`use component("digital/fifo", "digital", "tags/v1.0")
+use component("digital/sync_ff", "digital", "tags/v2.0")
+use component("analog/bias", "analog", "trunk")
+`)
+	new, _ := ParseDependencies(`use component("digital/fifo", "digital", "tags/v1.1")
+use component("digital/sync_ff", "digital", "tags/v2.0")
+use component("digital/crc", "digital", "tags/v0.3")
+`)
+
+	changes := DiffPins(old, new)
+	want := []PinChange{
+		{Name: "analog/bias", Old: "trunk"},
+		{Name: "digital/crc", New: "tags/v0.3"},
+		{Name: "digital/fifo", Old: "tags/v1.0", New: "tags/v1.1"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("DiffPins = %+v, want %+v", changes, want)
+	}
+
+	descriptions := []string{
+		"analog/bias: removed (was trunk)",
+		"digital/crc: added at tags/v0.3",
+		"digital/fifo: tags/v1.0 -> tags/v1.1",
+	}
+	for i, change := range changes {
+		if change.String() != descriptions[i] {
+			t.Errorf("String() = %q, want %q", change.String(), descriptions[i])
+		}
+	}
+}
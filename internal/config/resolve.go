@@ -0,0 +1,52 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// ResolveLocal walks the dependency tree using the depend.config files of
+// components that are checked out in the workspace. Components that are not
+// checked out are still returned, but their own dependencies are unknown.
+// The result is in breadth-first order starting with workspace.config.
+func (p *Parser) ResolveLocal() ([]*component.Component, error) {
+	queue := p.workspace.Ordered()
+	visited := make(map[string]bool)
+	var resolved []*component.Component
+
+	for len(queue) > 0 {
+		comp := queue[0]
+		queue = queue[1:]
+
+		if visited[comp.Name] {
+			continue
+		}
+		visited[comp.Name] = true
+		resolved = append(resolved, comp)
+
+		// Local references have no depend.config to follow
+		if comp.VCS == "local" {
+			continue
+		}
+
+		dependConfigPath := filepath.Join(p.workspace.Root, comp.Path, "depend.config")
+		if _, err := p.ParseDependConfig(comp, dependConfigPath); err != nil {
+			if strings.Contains(err.Error(), "dependency conflict") {
+				return resolved, err
+			}
+			// A broken depend.config only affects that component's subtree
+			continue
+		}
+
+		// component.info takes precedence over the meta block in depend.config.
+		// Metadata is informational, so a broken file does not stop resolution.
+		LoadInfo(comp, filepath.Join(p.workspace.Root, comp.Path, component.InfoFile))
+
+		// Use the recorded dependencies so components parsed earlier are followed too
+		queue = append(queue, comp.Dependencies...)
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+func TestResolveLocal(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"workspace.config": `use component("digital/top", "digital", "trunk")
+use component("setup/analog", "setup")
+`,
+		"digital/top/depend.config": `use component("digital/spi_master", "digital", "trunk")
+use component("analog/bias", "analog", "tags/v1.0")
+`,
+		"digital/spi_master/depend.config": `use component("digital/sync_ff", "digital", "trunk")
+use component("analog/bias", "analog", "tags/v1.0")
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	ws := component.NewWorkspace(tmpDir)
+	parser := NewParser(ws)
+	if err := parser.ParseWorkspaceConfig(ws.Config); err != nil {
+		t.Fatalf("Failed to parse workspace.config: %v", err)
+	}
+
+	resolved, err := parser.ResolveLocal()
+	if err != nil {
+		t.Fatalf("ResolveLocal failed: %v", err)
+	}
+
+	expected := []string{"digital/top", "setup/analog", "digital/spi_master", "analog/bias", "digital/sync_ff"}
+	if len(resolved) != len(expected) {
+		t.Fatalf("Expected %d components, got %d", len(expected), len(resolved))
+	}
+	for i, comp := range resolved {
+		if comp.Name != expected[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, expected[i], comp.Name)
+		}
+	}
+
+	// Shared dependencies must be the same instance for every parent
+	top, _ := ws.GetComponent("digital/top")
+	spi, _ := ws.GetComponent("digital/spi_master")
+	if top.Dependencies[1] != spi.Dependencies[1] {
+		t.Error("Expected analog/bias to be shared between parents")
+	}
+}
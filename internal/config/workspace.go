@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 // FindWorkspaceRoot searches for workspace.config starting from current directory
@@ -39,6 +41,45 @@ func WorkspaceExists(dir string) bool {
 	return err == nil
 }
 
+// SetWorkspaceOption sets a "set <key> \"<value>\"" directive in workspace.config.
+// An existing directive is replaced in place; otherwise the directive is added
+// after the last existing set directive, or at the top of the file.
+func SetWorkspaceOption(configPath, key, value string) error {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read workspace.config: %w", err)
+	}
+
+	directive := fmt.Sprintf("set %s \"%s\"", key, value)
+	keyPattern := regexp.MustCompile(`^\s*set\s+` + regexp.QuoteMeta(key) + `\s+"[^"]*"`)
+	setPattern := regexp.MustCompile(`^\s*set\s+\w+\s+"[^"]*"`)
+
+	lines := strings.Split(string(content), "\n")
+	lastSet := -1
+	replaced := false
+	for i, line := range lines {
+		if keyPattern.MatchString(line) {
+			lines[i] = directive
+			replaced = true
+			break
+		}
+		if setPattern.MatchString(line) {
+			lastSet = i
+		}
+	}
+
+	if !replaced {
+		insertAt := lastSet + 1
+		lines = append(lines[:insertAt], append([]string{directive}, lines[insertAt:]...)...)
+	}
+
+	if err := os.WriteFile(configPath, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write workspace.config: %w", err)
+	}
+
+	return nil
+}
+
 // CreateWorkspaceConfig creates a new workspace.config with example content
 func CreateWorkspaceConfig(dir string) error {
 	configPath := filepath.Join(dir, "workspace.config")
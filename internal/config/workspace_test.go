@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetWorkspaceOption(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "replace_existing",
+			content:  "# config\nset repo \"icworks\"\nset svn_url \"svn://anyvej11.dk\"\nuse component(\"setup/analog\")\n",
+			expected: "# config\nset repo \"icworks\"\nset svn_url \"svn://g9\"\nuse component(\"setup/analog\")\n",
+		},
+		{
+			name:     "insert_after_repo",
+			content:  "# config\nset repo \"icworks\"\nuse component(\"setup/analog\")\n",
+			expected: "# config\nset repo \"icworks\"\nset svn_url \"svn://g9\"\nuse component(\"setup/analog\")\n",
+		},
+		{
+			name:     "insert_at_top",
+			content:  "use component(\"setup/analog\")\n",
+			expected: "set svn_url \"svn://g9\"\nuse component(\"setup/analog\")\n",
+		},
+		{
+			name:     "commented_directive_ignored",
+			content:  "# set svn_url \"svn://old\"\nset repo \"icworks\"\n",
+			expected: "# set svn_url \"svn://old\"\nset repo \"icworks\"\nset svn_url \"svn://g9\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "workspace.config")
+			if err := os.WriteFile(configPath, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("Failed to create workspace.config: %v", err)
+			}
+
+			if err := SetWorkspaceOption(configPath, "svn_url", "svn://g9"); err != nil {
+				t.Fatalf("SetWorkspaceOption failed: %v", err)
+			}
+
+			content, _ := os.ReadFile(configPath)
+			if string(content) != tc.expected {
+				t.Errorf("Unexpected content:\n%s\nexpected:\n%s", content, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package export
+
+import "encoding/json"
+
+// edamFile is a file entry in an Edalize EDAM description
+type edamFile struct {
+	Name          string `json:"name"`
+	FileType      string `json:"file_type"`
+	IsIncludeFile bool   `json:"is_include_file,omitempty"`
+	LogicalName   string `json:"logical_name,omitempty"`
+}
+
+// edamParameter is a define in an Edalize EDAM description
+type edamParameter struct {
+	Datatype  string `json:"datatype"`
+	Default   string `json:"default,omitempty"`
+	Paramtype string `json:"paramtype"`
+}
+
+// Edalize returns an EDAM description as JSON, which edalize tools accept
+// as their edam argument
+func Edalize(p *Project) (string, error) {
+	edam := struct {
+		Name       string                   `json:"name"`
+		Toplevel   string                   `json:"toplevel,omitempty"`
+		Files      []edamFile               `json:"files"`
+		Parameters map[string]edamParameter `json:"parameters,omitempty"`
+	}{
+		Name:     p.Name,
+		Toplevel: p.Top,
+		Files:    []edamFile{},
+	}
+
+	for _, file := range p.Files {
+		edam.Files = append(edam.Files, edamFile{
+			Name:          file,
+			FileType:      fileType(file),
+			IsIncludeFile: IsHeader(file),
+			LogicalName:   p.Library(file),
+		})
+	}
+	for _, def := range p.Defines() {
+		if edam.Parameters == nil {
+			edam.Parameters = make(map[string]edamParameter)
+		}
+		edam.Parameters[def.Name] = edamParameter{Datatype: "str", Default: def.Value, Paramtype: "vlogdefine"}
+	}
+
+	data, err := json.MarshalIndent(edam, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
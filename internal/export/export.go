@@ -0,0 +1,195 @@
+// Package export writes tool project files for the HDL of a workspace
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/target"
+)
+
+// fileSuffixes are appended to the project name for targets writing one file
+var fileSuffixes = map[string]string{
+	"filelist":  ".f",
+	"verilator": ".verilator.f",
+	"yosys":     ".ys",
+	"openroad":  ".mk",
+	"edalize":   ".edam.json",
+	"make":      ".hdl.mk",
+	"tcl":       ".tcl",
+}
+
+// Component is the HDL of one component in the flow being exported
+type Component struct {
+	Name    string             // Component name, e.g. "digital/spi"
+	Type    string             // Component type, e.g. "digital"
+	Branch  string             // Branch or tag, e.g. "trunk" or "tags/v1.2.0"
+	Library string             // VHDL library; empty for the default work library
+	Files   []string           // Absolute paths in compile order, including headers
+	Incdirs []string           // Absolute include directories
+	Defines []component.Define // Including those inherited from dependencies
+	Own     []component.Define // Declared by the component itself
+	Deps    []string           // Names of the direct dependencies
+}
+
+// Project is a complete design to export
+type Project struct {
+	Name       string      // Project name, used for output file names
+	Flow       string      // Flow the files were selected for
+	Top        string      // Top-level module; empty if unknown
+	Files      []string    // All files in compile order, dependencies first
+	Components []Component // Components, dependencies first
+}
+
+// Incdirs returns the include directories of all components without duplicates
+func (p *Project) Incdirs() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, comp := range p.Components {
+		for _, dir := range comp.Incdirs {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
+// Defines returns the defines declared by all components. A later definition
+// of the same name replaces an earlier one, so the using component wins.
+func (p *Project) Defines() []component.Define {
+	var defines []component.Define
+	for _, comp := range p.Components {
+		defines = component.MergeDefines(defines, comp.Own...)
+	}
+	return defines
+}
+
+// Library returns the VHDL library a file is compiled into, or "" for work
+func (p *Project) Library(file string) string {
+	for _, comp := range p.Components {
+		if comp.Library != "" && isVHDL(file) && slices.Contains(comp.Files, file) {
+			return comp.Library
+		}
+	}
+	return ""
+}
+
+// Sources returns the files that are compiled, leaving out headers, which
+// are only reached through include directories
+func (p *Project) Sources() []string {
+	var sources []string
+	for _, file := range p.Files {
+		if !IsHeader(file) {
+			sources = append(sources, file)
+		}
+	}
+	return sources
+}
+
+// HeaderDirs returns the sorted directories containing header files
+func HeaderDirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if IsHeader(file) && !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// IsHeader reports whether file is a Verilog include file
+func IsHeader(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".vh" || ext == ".svh"
+}
+
+// isVHDL reports whether file is a VHDL source
+func isVHDL(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".vhd" || ext == ".vhdl"
+}
+
+// fileType returns the FuseSoC/Edalize file type of a file
+func fileType(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".sv", ".svh":
+		return "systemVerilogSource"
+	case ".vhd", ".vhdl":
+		return "vhdlSource"
+	}
+	return "verilogSource"
+}
+
+// Render returns the content of the project file for a target that writes
+// a single file; fusesoc writes one file per component and is not supported
+func Render(targetName string, p *Project) (string, error) {
+	switch targetName {
+	case "filelist":
+		return Filelist(p), nil
+	case "verilator":
+		return Verilator(p), nil
+	case "yosys":
+		return Yosys(p), nil
+	case "openroad":
+		return OpenROAD(p), nil
+	case "edalize":
+		return Edalize(p)
+	case "make":
+		return Make(p), nil
+	case "tcl":
+		return TCL(p), nil
+	case target.PerComponent:
+		return "", fmt.Errorf("target fusesoc writes one file per component")
+	}
+	return "", fmt.Errorf("unknown target %q (supported: %s)", targetName, strings.Join(target.Names, ", "))
+}
+
+// Generate writes the project files for target into outDir and returns
+// the paths written
+func Generate(targetName string, p *Project, outDir string) ([]string, error) {
+	var outputs map[string]string
+	if targetName == target.PerComponent {
+		var err error
+		outputs, err = FuseSoC(p, outDir)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		content, err := Render(targetName, p)
+		if err != nil {
+			return nil, err
+		}
+		outputs = map[string]string{p.Name + fileSuffixes[targetName]: content}
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+
+	var written []string
+	for name, content := range outputs {
+		path := filepath.Join(outDir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	sort.Strings(written)
+	return written, nil
+}
+
+// header returns the comment written at the top of generated files
+func header(comment string, p *Project) string {
+	return fmt.Sprintf("%s Generated by icw export for %s (%s flow) - do not edit\n", comment, p.Name, p.Flow)
+}
@@ -0,0 +1,236 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+func testProject() *Project {
+	return &Project{
+		Name: "top",
+		Flow: "rtl-sim",
+		Top:  "top",
+		Files: []string{
+			"/ws/digital/lib/inc/defs.svh",
+			"/ws/digital/lib/sync_ff.sv",
+			"/ws/digital/lib/fifo.vhd",
+			"/ws/digital/top/top.v",
+		},
+		Components: []Component{
+			{
+				Name:    "digital/lib",
+				Type:    "digital",
+				Branch:  "tags/v1.2.0",
+				Library: "lib",
+				Files:   []string{"/ws/digital/lib/inc/defs.svh", "/ws/digital/lib/sync_ff.sv", "/ws/digital/lib/fifo.vhd"},
+				Incdirs: []string{"/ws/digital/lib/inc"},
+				Defines: []component.Define{{Name: "WIDTH", Value: "8"}},
+				Own:     []component.Define{{Name: "WIDTH", Value: "8"}},
+			},
+			{
+				Name:    "digital/top",
+				Type:    "digital",
+				Branch:  "trunk",
+				Files:   []string{"/ws/digital/top/top.v"},
+				Defines: []component.Define{{Name: "WIDTH", Value: "16"}, {Name: "SIM"}},
+				Own:     []component.Define{{Name: "WIDTH", Value: "16"}, {Name: "SIM"}},
+				Deps:    []string{"digital/lib"},
+			},
+		},
+	}
+}
+
+func TestFilelist(t *testing.T) {
+	expected := `+incdir+/ws/digital/lib/inc
++define+WIDTH=16
++define+SIM
+/ws/digital/lib/sync_ff.sv
+/ws/digital/lib/fifo.vhd
+/ws/digital/top/top.v
+`
+	got := Filelist(testProject())
+	if !strings.HasPrefix(got, "//") || !strings.HasSuffix(got, expected) {
+		t.Errorf("Unexpected filelist:\n%s", got)
+	}
+}
+
+func TestVerilator(t *testing.T) {
+	got := Verilator(testProject())
+	if !strings.Contains(got, "--top-module top\n") {
+		t.Errorf("Missing --top-module:\n%s", got)
+	}
+	if !strings.Contains(got, "// VHDL not supported: /ws/digital/lib/fifo.vhd\n") {
+		t.Errorf("VHDL should be commented out:\n%s", got)
+	}
+}
+
+func TestYosys(t *testing.T) {
+	got := Yosys(testProject())
+	if !strings.Contains(got, "read_verilog -sv -I/ws/digital/lib/inc -DWIDTH=16 -DSIM /ws/digital/top/top.v\n") {
+		t.Errorf("Unexpected read_verilog lines:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "hierarchy -check -top top\n") {
+		t.Errorf("Missing hierarchy command:\n%s", got)
+	}
+	if strings.Contains(got, "read_verilog -sv -I/ws/digital/lib/inc -DWIDTH=16 -DSIM /ws/digital/lib/fifo.vhd") {
+		t.Errorf("VHDL must not be read with read_verilog:\n%s", got)
+	}
+}
+
+func TestOpenROAD(t *testing.T) {
+	got := OpenROAD(testProject())
+	expected := "export DESIGN_NAME = top\n" +
+		"export VERILOG_FILES = \\\n\t/ws/digital/lib/sync_ff.sv \\\n\t/ws/digital/top/top.v\n" +
+		"export VERILOG_INCLUDE_DIRS = \\\n\t/ws/digital/lib/inc\n" +
+		"export VERILOG_DEFINES = \\\n\t-DWIDTH=16 \\\n\t-DSIM\n"
+	if !strings.HasSuffix(got, expected) {
+		t.Errorf("Unexpected make fragment:\n%s", got)
+	}
+}
+
+func TestMake(t *testing.T) {
+	got := Make(testProject())
+	expected := "export HDL_TOP = top\n" +
+		"export HDL_FILES = \\\n\t/ws/digital/lib/sync_ff.sv \\\n\t/ws/digital/lib/fifo.vhd \\\n\t/ws/digital/top/top.v\n" +
+		"export HDL_INCDIRS = \\\n\t/ws/digital/lib/inc\n" +
+		"export HDL_DEFINES = \\\n\tWIDTH=16 \\\n\tSIM\n"
+	if !strings.HasSuffix(got, expected) {
+		t.Errorf("Unexpected make fragment:\n%s", got)
+	}
+}
+
+func TestTCL(t *testing.T) {
+	got := TCL(testProject())
+	expected := "set HDL_TOP {top}\n" +
+		"set HDL_FILES [list \\\n\t{/ws/digital/lib/sync_ff.sv} \\\n\t{/ws/digital/lib/fifo.vhd} \\\n\t{/ws/digital/top/top.v}]\n" +
+		"set HDL_INCDIRS [list \\\n\t{/ws/digital/lib/inc}]\n" +
+		"set HDL_DEFINES [list \\\n\t{WIDTH=16} \\\n\t{SIM}]\n"
+	if !strings.HasSuffix(got, expected) {
+		t.Errorf("Unexpected Tcl script:\n%s", got)
+	}
+}
+
+func TestFuseSoC(t *testing.T) {
+	cores, err := FuseSoC(testProject(), "/ws/build")
+	if err != nil {
+		t.Fatalf("FuseSoC failed: %v", err)
+	}
+
+	lib := cores["digital_lib.core"]
+	if !strings.HasPrefix(lib, "CAPI=2:\n") || !strings.Contains(lib, "name: icw:digital:lib:1.2.0\n") {
+		t.Errorf("Unexpected core header:\n%s", lib)
+	}
+	if !strings.Contains(lib, "      - ../digital/lib/inc/defs.svh: {file_type: systemVerilogSource, is_include_file: true, include_path: ../digital/lib/inc}\n") {
+		t.Errorf("Missing include file:\n%s", lib)
+	}
+	if !strings.Contains(lib, "      - ../digital/lib/fifo.vhd: {file_type: vhdlSource, logical_name: lib}\n") {
+		t.Errorf("Missing VHDL library:\n%s", lib)
+	}
+	if !strings.Contains(lib, "paramtype: vlogdefine\n    default: \"8\"\n") {
+		t.Errorf("Missing define parameter:\n%s", lib)
+	}
+
+	top := cores["digital_top.core"]
+	if !strings.Contains(top, "name: icw:digital:top:0\n") || !strings.Contains(top, "    depend:\n      - icw:digital:lib\n") {
+		t.Errorf("Unexpected top core:\n%s", top)
+	}
+}
+
+func TestEdalize(t *testing.T) {
+	got, err := Edalize(testProject())
+	if err != nil {
+		t.Fatalf("Edalize failed: %v", err)
+	}
+
+	var edam struct {
+		Name     string
+		Toplevel string
+		Files    []struct {
+			Name          string `json:"name"`
+			FileType      string `json:"file_type"`
+			IsIncludeFile bool   `json:"is_include_file"`
+			LogicalName   string `json:"logical_name"`
+		}
+		Parameters map[string]map[string]string
+	}
+	if err := json.Unmarshal([]byte(got), &edam); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, got)
+	}
+
+	if edam.Toplevel != "top" || len(edam.Files) != 4 {
+		t.Errorf("Unexpected EDAM: %+v", edam)
+	}
+	if !edam.Files[0].IsIncludeFile || edam.Files[2].FileType != "vhdlSource" || edam.Files[2].LogicalName != "lib" {
+		t.Errorf("Unexpected file entries: %+v", edam.Files)
+	}
+	if edam.Parameters["WIDTH"]["default"] != "16" || edam.Parameters["SIM"]["paramtype"] != "vlogdefine" {
+		t.Errorf("Unexpected parameters: %+v", edam.Parameters)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	outDir := t.TempDir()
+
+	written, err := Generate("fusesoc", testProject(), outDir)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if len(written) != 2 || filepath.Base(written[0]) != "digital_lib.core" {
+		t.Errorf("Unexpected files: %v", written)
+	}
+	if _, err := os.Stat(written[1]); err != nil {
+		t.Errorf("Core file not written: %v", err)
+	}
+
+	if _, err := Generate("quartus", testProject(), outDir); err == nil {
+		t.Error("Expected error for unknown target")
+	}
+
+	written, err = Generate("make", testProject(), outDir)
+	if err != nil || len(written) != 1 || filepath.Base(written[0]) != "top.hdl.mk" {
+		t.Errorf("Unexpected make output: %v, %v", written, err)
+	}
+	if _, err := Render("fusesoc", testProject()); err == nil {
+		t.Error("Render must reject fusesoc")
+	}
+}
+
+func TestLibrary(t *testing.T) {
+	p := testProject()
+	if got := p.Library("/ws/digital/lib/fifo.vhd"); got != "lib" {
+		t.Errorf("Library of VHDL file = %q, expected lib", got)
+	}
+	if got := p.Library("/ws/digital/lib/sync_ff.sv"); got != "" {
+		t.Errorf("Verilog files have no library, got %q", got)
+	}
+}
+
+func TestProjectDefines(t *testing.T) {
+	// a overrides the define of lib, b inherits it unchanged; the override
+	// must not be undone by b coming later
+	p := &Project{Components: []Component{
+		{Name: "digital/lib", Defines: []component.Define{{Name: "WIDTH", Value: "8"}}, Own: []component.Define{{Name: "WIDTH", Value: "8"}}},
+		{Name: "digital/a", Defines: []component.Define{{Name: "WIDTH", Value: "16"}}, Own: []component.Define{{Name: "WIDTH", Value: "16"}}},
+		{Name: "digital/b", Defines: []component.Define{{Name: "WIDTH", Value: "8"}, {Name: "SIM"}}, Own: []component.Define{{Name: "SIM"}}},
+	}}
+
+	var got []string
+	for _, def := range p.Defines() {
+		got = append(got, def.String())
+	}
+	if strings.Join(got, " ") != "WIDTH=16 SIM" {
+		t.Errorf("Defines = %v, expected [WIDTH=16 SIM]", got)
+	}
+}
+
+func TestHeaderDirs(t *testing.T) {
+	files := []string{"/a/inc/x.svh", "/a/rtl/top.sv", "/a/inc/y.vh", "/b/defs.vh"}
+	if got := strings.Join(HeaderDirs(files), " "); got != "/a/inc /b" {
+		t.Errorf("HeaderDirs = %q, expected %q", got, "/a/inc /b")
+	}
+}
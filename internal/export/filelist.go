@@ -0,0 +1,49 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Filelist returns a simulator .f file with +incdir+ and +define+ options
+// followed by the sources in compile order
+func Filelist(p *Project) string {
+	var b strings.Builder
+	b.WriteString(header("//", p))
+
+	for _, dir := range p.Incdirs() {
+		fmt.Fprintf(&b, "+incdir+%s\n", dir)
+	}
+	for _, def := range p.Defines() {
+		fmt.Fprintf(&b, "+define+%s\n", def)
+	}
+	for _, file := range p.Sources() {
+		fmt.Fprintln(&b, file)
+	}
+	return b.String()
+}
+
+// Verilator returns a Verilator command file. VHDL sources are left out as
+// Verilator only reads Verilog and SystemVerilog.
+func Verilator(p *Project) string {
+	var b strings.Builder
+	b.WriteString(header("//", p))
+
+	if p.Top != "" {
+		fmt.Fprintf(&b, "--top-module %s\n", p.Top)
+	}
+	for _, dir := range p.Incdirs() {
+		fmt.Fprintf(&b, "+incdir+%s\n", dir)
+	}
+	for _, def := range p.Defines() {
+		fmt.Fprintf(&b, "+define+%s\n", def)
+	}
+	for _, file := range p.Sources() {
+		if isVHDL(file) {
+			fmt.Fprintf(&b, "// VHDL not supported: %s\n", file)
+			continue
+		}
+		fmt.Fprintln(&b, file)
+	}
+	return b.String()
+}
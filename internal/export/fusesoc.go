@@ -0,0 +1,119 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FuseSoC returns one CAPI2 .core file per component, keyed by file name.
+// File paths are relative to outDir, where the core files are written.
+func FuseSoC(p *Project, outDir string) (map[string]string, error) {
+	cores := make(map[string]string)
+
+	for _, comp := range p.Components {
+		var b strings.Builder
+		b.WriteString("CAPI=2:\n")
+		b.WriteString(header("#", p))
+		fmt.Fprintf(&b, "name: %s\n", coreName(comp, true))
+		fmt.Fprintf(&b, "description: %s (%s)\n\n", comp.Name, comp.Branch)
+
+		b.WriteString("filesets:\n  rtl:\n")
+		if len(comp.Files) > 0 {
+			b.WriteString("    files:\n")
+			for _, file := range comp.Files {
+				rel, err := filepath.Rel(outDir, file)
+				if err != nil {
+					return nil, err
+				}
+				attrs := "file_type: " + fileType(file)
+				if IsHeader(file) {
+					attrs += ", is_include_file: true"
+					// Declared include directories, e.g. for `include "sub/defs.vh"
+					if dir := includeDir(file, comp.Incdirs); dir != "" {
+						relDir, err := filepath.Rel(outDir, dir)
+						if err != nil {
+							return nil, err
+						}
+						attrs += ", include_path: " + filepath.ToSlash(relDir)
+					}
+				}
+				if comp.Library != "" && isVHDL(file) {
+					attrs += ", logical_name: " + comp.Library
+				}
+				fmt.Fprintf(&b, "      - %s: {%s}\n", filepath.ToSlash(rel), attrs)
+			}
+		}
+		if len(comp.Deps) > 0 {
+			b.WriteString("    depend:\n")
+			for _, dep := range comp.Deps {
+				if depComp, ok := findComponent(p, dep); ok {
+					fmt.Fprintf(&b, "      - %s\n", coreName(depComp, false))
+				}
+			}
+		}
+
+		b.WriteString("\ntargets:\n  default:\n    filesets: [rtl]\n")
+		if defines := comp.Defines; len(defines) > 0 {
+			b.WriteString("    parameters: [")
+			for i, def := range defines {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(def.Name)
+			}
+			b.WriteString("]\n\nparameters:\n")
+			for _, def := range defines {
+				fmt.Fprintf(&b, "  %s:\n    datatype: str\n    paramtype: vlogdefine\n", def.Name)
+				if def.Value != "" {
+					fmt.Fprintf(&b, "    default: %q\n", def.Value)
+				}
+			}
+		}
+
+		cores[strings.ReplaceAll(comp.Name, "/", "_")+".core"] = b.String()
+	}
+
+	return cores, nil
+}
+
+// includeDir returns the deepest of the include directories containing
+// file, or "" if none does
+func includeDir(file string, incdirs []string) string {
+	found := ""
+	for _, dir := range incdirs {
+		if strings.HasPrefix(file, dir+string(filepath.Separator)) && len(dir) > len(found) {
+			found = dir
+		}
+	}
+	return found
+}
+
+var (
+	vlnvInvalid = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+	tagVersion  = regexp.MustCompile(`^tags/v?(\d+(?:\.\d+)*)$`)
+)
+
+// coreName returns the VLNV name of a component, icw:<type>:<name>[:<version>].
+// Tags like tags/v1.2.0 become the version; branches have none.
+func coreName(comp Component, withVersion bool) string {
+	name := vlnvInvalid.ReplaceAllString(filepath.Base(comp.Name), "_")
+	vlnv := fmt.Sprintf("icw:%s:%s", comp.Type, name)
+	if !withVersion {
+		return vlnv
+	}
+	if m := tagVersion.FindStringSubmatch(comp.Branch); m != nil {
+		return vlnv + ":" + m[1]
+	}
+	return vlnv + ":0"
+}
+
+func findComponent(p *Project, name string) (Component, bool) {
+	for _, comp := range p.Components {
+		if comp.Name == name {
+			return comp, true
+		}
+	}
+	return Component{}, false
+}
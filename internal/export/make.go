@@ -0,0 +1,55 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Make returns a make fragment with the sources, include directories,
+// defines and top-level module in generic HDL_* variables
+func Make(p *Project) string {
+	var b strings.Builder
+	b.WriteString(header("#", p))
+
+	if p.Top != "" {
+		fmt.Fprintf(&b, "export HDL_TOP = %s\n", p.Top)
+	}
+	writeMakeList(&b, "HDL_FILES", p.Sources())
+	writeMakeList(&b, "HDL_INCDIRS", p.Incdirs())
+
+	var defines []string
+	for _, def := range p.Defines() {
+		defines = append(defines, def.String())
+	}
+	writeMakeList(&b, "HDL_DEFINES", defines)
+
+	return b.String()
+}
+
+// TCL returns a Tcl script setting the same HDL_* variables as Make, as
+// lists for vendor tools to source
+func TCL(p *Project) string {
+	var b strings.Builder
+	b.WriteString(header("#", p))
+
+	fmt.Fprintf(&b, "set HDL_TOP {%s}\n", p.Top)
+	writeTCLList(&b, "HDL_FILES", p.Sources())
+	writeTCLList(&b, "HDL_INCDIRS", p.Incdirs())
+
+	var defines []string
+	for _, def := range p.Defines() {
+		defines = append(defines, def.String())
+	}
+	writeTCLList(&b, "HDL_DEFINES", defines)
+
+	return b.String()
+}
+
+// writeTCLList sets a Tcl variable to a list with one braced value per line
+func writeTCLList(b *strings.Builder, name string, values []string) {
+	fmt.Fprintf(b, "set %s [list", name)
+	for _, v := range values {
+		fmt.Fprintf(b, " \\\n\t{%s}", v)
+	}
+	b.WriteString("]\n")
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Yosys returns a Yosys script that reads the sources and checks the
+// hierarchy. VHDL sources need the GHDL plugin and are left out.
+func Yosys(p *Project) string {
+	var b strings.Builder
+	b.WriteString(header("#", p))
+
+	var opts []string
+	for _, dir := range p.Incdirs() {
+		opts = append(opts, "-I"+dir)
+	}
+	for _, def := range p.Defines() {
+		opts = append(opts, "-D"+def.String())
+	}
+	prefix := "read_verilog -sv"
+	if len(opts) > 0 {
+		prefix += " " + strings.Join(opts, " ")
+	}
+
+	for _, file := range p.Sources() {
+		if isVHDL(file) {
+			fmt.Fprintf(&b, "# VHDL needs the ghdl plugin: %s\n", file)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s\n", prefix, file)
+	}
+
+	if p.Top != "" {
+		fmt.Fprintf(&b, "hierarchy -check -top %s\n", p.Top)
+	} else {
+		b.WriteString("hierarchy -check -auto-top\n")
+	}
+	return b.String()
+}
+
+// OpenROAD returns a make fragment with the design variables used by
+// OpenROAD Flow Scripts, to be included from a design config.mk
+func OpenROAD(p *Project) string {
+	var b strings.Builder
+	b.WriteString(header("#", p))
+
+	if p.Top != "" {
+		fmt.Fprintf(&b, "export DESIGN_NAME = %s\n", p.Top)
+	}
+
+	var files []string
+	for _, file := range p.Sources() {
+		if !isVHDL(file) {
+			files = append(files, file)
+		}
+	}
+	writeMakeList(&b, "VERILOG_FILES", files)
+	writeMakeList(&b, "VERILOG_INCLUDE_DIRS", p.Incdirs())
+
+	var defines []string
+	for _, def := range p.Defines() {
+		defines = append(defines, "-D"+def.String())
+	}
+	writeMakeList(&b, "VERILOG_DEFINES", defines)
+
+	return b.String()
+}
+
+// writeMakeList writes an exported make variable with one value per line
+func writeMakeList(b *strings.Builder, name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "export %s =", name)
+	for _, v := range values {
+		fmt.Fprintf(b, " \\\n\t%s", v)
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,134 @@
+package hdl
+
+import (
+	"sort"
+	"strings"
+)
+
+// Design is the HDL view of one component for Check
+type Design struct {
+	Component string
+	Deps      []string  // Transitive dependencies the component may use
+	Files     *HDLFiles // Discovered files of the component
+	Candidate bool      // Not part of the workspace; only used to suggest dependencies
+}
+
+// Reference is an instance or package reference whose definition is not
+// visible from the component that uses it
+type Reference struct {
+	Unit      string
+	File      string
+	Component string
+	DefinedIn []string // Components outside the visible set that define the unit
+}
+
+// CheckReport is the result of Check
+type CheckReport struct {
+	Tops       []Unit      // Modules, entities and programs nobody instantiates
+	Unresolved []Reference // References not defined in the component or its dependencies
+}
+
+// externalUnits are provided by simulators and libraries outside the workspace
+var externalUnits = map[string]bool{"uvm_pkg": true}
+
+// topKinds are the unit kinds that can be the top of an elaboration
+var topKinds = map[UnitKind]bool{UnitModule: true, UnitEntity: true, UnitProgram: true}
+
+// Check builds the instantiation graph of the files each design uses in
+// flow, finds the top-level units and reports references that cannot be
+// resolved from a component's own files and its declared dependencies.
+func Check(designs []Design, flow Flow) *CheckReport {
+	report := &CheckReport{}
+
+	// Where every unit is defined, across all designs
+	definedBy := make(map[string][]string)
+	files := make(map[string][]*FileInfo)
+	for _, design := range designs {
+		for _, path := range design.Files.ForFlow(flow) {
+			info, ok := design.Files.infos[path]
+			if !ok {
+				continue
+			}
+			files[design.Component] = append(files[design.Component], info)
+			for _, unit := range info.Units {
+				definedBy[unit.Name] = appendUnique(definedBy[unit.Name], design.Component)
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, design := range designs {
+		if design.Candidate {
+			continue
+		}
+
+		visible := map[string]bool{design.Component: true}
+		for _, dep := range design.Deps {
+			visible[dep] = true
+		}
+
+		for _, info := range files[design.Component] {
+			for _, name := range info.Uses {
+				owners := lookupDefinition(definedBy, name)
+				for _, owner := range owners {
+					used[owner+"\x00"+strings.ToLower(name)] = true
+				}
+
+				if externalUnits[name] || anyVisible(owners, visible) {
+					continue
+				}
+				report.Unresolved = append(report.Unresolved, Reference{
+					Unit:      name,
+					File:      info.Path,
+					Component: design.Component,
+					DefinedIn: owners,
+				})
+			}
+		}
+	}
+
+	for _, design := range designs {
+		if design.Candidate {
+			continue
+		}
+		for _, info := range files[design.Component] {
+			for _, unit := range info.Units {
+				if topKinds[unit.Kind] && !used[design.Component+"\x00"+strings.ToLower(unit.Name)] {
+					report.Tops = append(report.Tops, unit)
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Tops, func(i, j int) bool {
+		return report.Tops[i].File < report.Tops[j].File
+	})
+	return report
+}
+
+// lookupDefinition returns the components defining name, matching VHDL
+// names case-insensitively
+func lookupDefinition(definedBy map[string][]string, name string) []string {
+	if owners, ok := definedBy[name]; ok {
+		return owners
+	}
+	return definedBy[strings.ToLower(name)]
+}
+
+func anyVisible(owners []string, visible map[string]bool) bool {
+	for _, owner := range owners {
+		if visible[owner] {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
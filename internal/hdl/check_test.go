@@ -0,0 +1,85 @@
+package hdl
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func discoverDesign(t *testing.T, dir, name string, deps []string, files map[string]string) Design {
+	t.Helper()
+	compDir := filepath.Join(dir, filepath.FromSlash(name))
+	writeFiles(t, compDir, files)
+
+	hdlFiles, err := DiscoverFiles(compDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+	return Design{Component: name, Deps: deps, Files: hdlFiles}
+}
+
+func TestCheck(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	designs := []Design{
+		discoverDesign(t, tmpDir, "digital/lib", nil, map[string]string{
+			"sync_ff.v": "module sync_ff(); endmodule",
+		}),
+		discoverDesign(t, tmpDir, "digital/spi", nil, map[string]string{
+			"spi_master.v": "module spi_master(); endmodule",
+		}),
+		discoverDesign(t, tmpDir, "digital/top", []string{"digital/lib"}, map[string]string{
+			"top.sv":    "module top();\n  sync_ff u_sync ();\n  spi_master u_spi ();\n  uart u_uart ();\nendmodule",
+			"top_tb.sv": "module top_tb();\n  import uvm_pkg::*;\n  top dut ();\nendmodule",
+		}),
+	}
+
+	// An undeclared working copy that defines the missing uart
+	candidate := discoverDesign(t, tmpDir, "digital/uart", nil, map[string]string{
+		"uart.vhd": "entity UART is\nend entity;\narchitecture rtl of uart is\nbegin\nend;",
+	})
+	candidate.Candidate = true
+	designs = append(designs, candidate)
+
+	report := Check(designs, FlowRTLSim)
+
+	var tops []string
+	for _, unit := range report.Tops {
+		tops = append(tops, unit.Name)
+	}
+	// spi_master is used, but not visible from digital/top
+	if got := strings.Join(tops, " "); got != "top_tb" {
+		t.Errorf("Tops = %q, expected %q", got, "top_tb")
+	}
+
+	if len(report.Unresolved) != 2 {
+		t.Fatalf("Expected 2 unresolved references, got %+v", report.Unresolved)
+	}
+
+	spi := report.Unresolved[0]
+	if spi.Unit != "spi_master" || spi.Component != "digital/top" || strings.Join(spi.DefinedIn, ",") != "digital/spi" {
+		t.Errorf("Unexpected reference: %+v", spi)
+	}
+	uart := report.Unresolved[1]
+	if uart.Unit != "uart" || strings.Join(uart.DefinedIn, ",") != "digital/uart" {
+		t.Errorf("Unexpected reference: %+v", uart)
+	}
+}
+
+func TestCheckNotFound(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	designs := []Design{
+		discoverDesign(t, tmpDir, "digital/top", nil, map[string]string{
+			"top.v": "module top();\n  pll_ctrl u_pll ();\nendmodule",
+		}),
+	}
+
+	report := Check(designs, FlowRTLSim)
+	if len(report.Unresolved) != 1 || report.Unresolved[0].Unit != "pll_ctrl" || len(report.Unresolved[0].DefinedIn) != 0 {
+		t.Errorf("Unexpected unresolved references: %+v", report.Unresolved)
+	}
+	if len(report.Tops) != 1 || report.Tops[0].Name != "top" {
+		t.Errorf("Unexpected tops: %+v", report.Tops)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -15,6 +16,9 @@ const (
 	Package FileType = "package" // VHDL packages
 	RTL     FileType = "rtl"      // Synthesizable RTL
 	Behav   FileType = "behav"    // Behavioral/testbench
+	Gate    FileType = "gate"     // Gate-level netlists from synthesis or layout
+	Model   FileType = "model"    // Simulation models, e.g. of analog blocks
+	IP      FileType = "ip"       // Third-party IP delivered as HDL
 )
 
 // HDLFiles contains categorized HDL files for a component
@@ -22,6 +26,16 @@ type HDLFiles struct {
 	Package []string // VHDL package files
 	RTL     []string // Synthesizable RTL files
 	Behav   []string // Behavioral/testbench files
+	Gate    []string // Gate-level netlists
+	Model   []string // Simulation models
+	IP      []string // IP files
+
+	Units []Unit // Design units defined in the files above, by name
+
+	root    string                // Component directory
+	rules   []FlowRule            // Flow rules from depend.config
+	markers map[string][]FlowRule // Flow rules from // icw: markers, by file
+	infos   map[string]*FileInfo  // Scanned design units and references, by file
 }
 
 // Architecture name mappings for VHDL
@@ -35,12 +49,53 @@ var archMap = map[string]FileType{
 	"sim":        Behav,
 }
 
-// DiscoverFiles finds and categorizes HDL files in a component directory
+// Layout describes where HDL files live in a component
+type Layout struct {
+	Dirs    []string // Directories to search, relative to the component root; the root if empty
+	Exclude []string   // Ignore patterns, same syntax as .icwignore
+	Rules   []FlowRule // Per-flow include/exclude rules
+}
+
+// HDL file extensions by language
+var (
+	verilogExts = map[string]bool{".v": true, ".sv": true}
+	headerExts  = map[string]bool{".vh": true, ".svh": true}
+	vhdlExts    = map[string]bool{".vhd": true, ".vhdl": true}
+)
+
+// IsHDLFile reports whether path has an HDL file extension
+func IsHDLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return verilogExts[ext] || headerExts[ext] || vhdlExts[ext]
+}
+
+// Directories whose Verilog files are testbenches or simulation models
+var (
+	testbenchDirs = map[string]bool{"tb": true}
+	modelDirs     = map[string]bool{"model": true, "models": true}
+)
+
+// DiscoverFiles finds and categorizes HDL files anywhere in a component directory
 func DiscoverFiles(componentPath string) (*HDLFiles, error) {
+	return DiscoverFilesWithLayout(componentPath, Layout{})
+}
+
+// DiscoverFilesWithLayout finds and categorizes HDL files in the directories
+// of layout. Directories are searched recursively; hidden directories, paths
+// matching layout.Exclude and paths listed in .icwignore files are skipped.
+func DiscoverFilesWithLayout(componentPath string, layout Layout) (*HDLFiles, error) {
 	files := &HDLFiles{
 		Package: make([]string, 0),
 		RTL:     make([]string, 0),
 		Behav:   make([]string, 0),
+		Gate:    make([]string, 0),
+		Model:   make([]string, 0),
+		IP:      make([]string, 0),
+		Units:   make([]Unit, 0),
+		root:    componentPath,
+		rules:   layout.Rules,
+		markers: make(map[string][]FlowRule),
+		infos:   make(map[string]*FileInfo),
 	}
 
 	// Check if directory exists
@@ -48,77 +103,212 @@ func DiscoverFiles(componentPath string) (*HDLFiles, error) {
 		return files, nil // Return empty if component not checked out
 	}
 
-	// Find all HDL files
-	verilogFiles, err := filepath.Glob(filepath.Join(componentPath, "*.v"))
-	if err != nil {
-		return nil, err
+	ignore := NewIgnore(layout.Exclude)
+	loaded := make(map[string]bool) // Directories whose .icwignore has been read
+	loadIgnore := func(rel string) error {
+		if loaded[rel] {
+			return nil
+		}
+		loaded[rel] = true
+		return ignore.AddFile(rel, filepath.Join(componentPath, filepath.FromSlash(rel), IgnoreFile))
 	}
 
-	systemVerilogFiles, err := filepath.Glob(filepath.Join(componentPath, "*.sv"))
-	if err != nil {
-		return nil, err
+	dirs := layout.Dirs
+	if len(dirs) == 0 {
+		dirs = []string{"."}
 	}
 
-	systemVerilogHeaders, err := filepath.Glob(filepath.Join(componentPath, "*.svh"))
-	if err != nil {
-		return nil, err
-	}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		top := filepath.Join(componentPath, dir)
+		if info, err := os.Stat(top); err != nil || !info.IsDir() {
+			continue // Declared directories may not exist in every branch
+		}
 
-	vhdlFiles, err := filepath.Glob(filepath.Join(componentPath, "*.vhd"))
-	if err != nil {
-		return nil, err
-	}
+		// Ignore files above the search directory apply as well
+		rel := relSlash(componentPath, top)
+		if rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		parts := strings.Split(rel, "/")
+		for i := range parts {
+			if err := loadIgnore(strings.Join(parts[:i], "/")); err != nil {
+				return nil, err
+			}
+		}
 
-	// Process Verilog files
-	for _, file := range verilogFiles {
-		if strings.HasSuffix(file, "_tb.v") {
-			files.Behav = append(files.Behav, file)
-		} else {
-			files.RTL = append(files.RTL, file)
+		err := filepath.WalkDir(top, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel := relSlash(componentPath, path)
+
+			if d.IsDir() {
+				if path != componentPath && (strings.HasPrefix(d.Name(), ".") || ignore.Match(rel, true)) {
+					return filepath.SkipDir
+				}
+				if rel == "." {
+					rel = ""
+				}
+				return loadIgnore(rel)
+			}
+
+			if seen[path] || ignore.Match(rel, false) {
+				return nil
+			}
+			seen[path] = true
+
+			classifyFile(files, path, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	// Process SystemVerilog files
-	for _, file := range systemVerilogFiles {
-		if strings.HasSuffix(file, "_tb.sv") {
-			files.Behav = append(files.Behav, file)
-		} else {
-			files.RTL = append(files.RTL, file)
+	sort.Slice(files.Units, func(i, j int) bool {
+		if files.Units[i].Name != files.Units[j].Name {
+			return files.Units[i].Name < files.Units[j].Name
 		}
+		return files.Units[i].File < files.Units[j].File
+	})
+
+	// Sort by name first so compile order ties are broken the same way every run
+	for _, list := range []*[]string{&files.Package, &files.RTL, &files.Behav, &files.Gate, &files.Model, &files.IP} {
+		sort.Strings(*list)
+		*list = orderPaths(*list, files.infos)
 	}
 
-	// Process SystemVerilog headers
-	for _, file := range systemVerilogHeaders {
-		files.RTL = append(files.RTL, file)
+	return files, nil
+}
+
+// scanFileOrEmpty scans an HDL file, or returns no units if it cannot be read
+func scanFileOrEmpty(path string) *FileInfo {
+	info, err := ScanFile(path)
+	if err != nil {
+		return &FileInfo{Path: path}
 	}
+	return info
+}
+
+// classifyFile adds an HDL file to the matching category. Files with other
+// extensions and VHDL files that cannot be read are skipped.
+//
+// The type follows from the extension and naming conventions (*_tb, *_gate,
+// *_netlist, *_model, tb/, models/ and ip/ directories), then from the
+// content: Verilog files with testbench constructs are behavioral and files
+// that only define packages are packages. An "icw:" marker comment at the
+// top of the file overrides all of this.
+func classifyFile(files *HDLFiles, path, rel string) {
+	ext := strings.ToLower(filepath.Ext(path))
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	parent := dirs[len(dirs)-1]
+
+	// Only HDL files are read; components also hold waveforms, OA views and PDFs
+	var info *FileInfo
+	var fileType FileType
+	switch {
+	case verilogExts[ext]:
+		// Gate-level netlists can be huge and only instantiate cells, so like
+		// readMarkers, their units are not scanned
+		if strings.HasSuffix(base, "_gate") || strings.HasSuffix(base, "_netlist") {
+			info = &FileInfo{Path: path}
+			fileType = Gate
+			break
+		}
+
+		info = scanFileOrEmpty(path)
+		switch {
+		case strings.HasSuffix(base, "_model") || modelDirs[parent]:
+			fileType = Model
+		case strings.HasSuffix(base, "_tb") || testbenchDirs[parent] || info.Testbench:
+			fileType = Behav
+		case info.HasUnit(UnitPackage) && !info.HasUnit(UnitModule) && !info.HasUnit(UnitInterface):
+			fileType = Package
+		default:
+			fileType = RTL
+		}
 
-	// Process VHDL files
-	packages := make(map[string]bool)
-	for _, file := range vhdlFiles {
-		fileType, isPackage, err := classifyVHDLFile(file)
+	case headerExts[ext]:
+		info = scanFileOrEmpty(path)
+		fileType = RTL
+
+	case vhdlExts[ext]:
+		info = scanFileOrEmpty(path)
+		vhdlType, isPackage, err := classifyVHDLFile(path)
 		if err != nil {
 			// If we can't classify, skip it
-			continue
+			return
 		}
-
 		if isPackage {
-			packages[file] = true
+			fileType = Package
 		} else {
-			switch fileType {
-			case RTL:
-				files.RTL = append(files.RTL, file)
-			case Behav:
-				files.Behav = append(files.Behav, file)
-			}
+			fileType = vhdlType
 		}
+
+	default:
+		return
 	}
 
-	// Add packages
-	for pkg := range packages {
-		files.Package = append(files.Package, pkg)
+	// Files delivered as IP keep their own structure below ip/
+	for _, dir := range dirs {
+		if dir == "ip" && fileType != Package {
+			fileType = IP
+		}
 	}
 
-	return files, nil
+	markerType, rules := readMarkers(path)
+	if markerType != "" {
+		fileType = markerType
+	}
+	if len(rules) > 0 {
+		files.markers[path] = rules
+	}
+
+	if fileType == "" {
+		return
+	}
+
+	files.add(fileType, path)
+	files.infos[path] = info
+	files.Units = append(files.Units, info.Units...)
+}
+
+// add appends a file to the list of its type
+func (f *HDLFiles) add(fileType FileType, path string) {
+	switch fileType {
+	case Package:
+		f.Package = append(f.Package, path)
+	case RTL:
+		f.RTL = append(f.RTL, path)
+	case Behav:
+		f.Behav = append(f.Behav, path)
+	case Gate:
+		f.Gate = append(f.Gate, path)
+	case Model:
+		f.Model = append(f.Model, path)
+	case IP:
+		f.IP = append(f.IP, path)
+	}
+}
+
+// All returns every HDL file of the component in compile order
+func (f *HDLFiles) All() []string {
+	var all []string
+	for _, files := range [][]string{f.Package, f.IP, f.Model, f.Gate, f.RTL, f.Behav} {
+		all = append(all, files...)
+	}
+	return orderPaths(all, f.infos)
+}
+
+// relSlash returns path relative to base with forward slashes
+func relSlash(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
 }
 
 // classifyVHDLFile reads a VHDL file and determines its type based on architecture name
@@ -134,8 +324,10 @@ func classifyVHDLFile(filePath string) (FileType, bool, error) {
 	// Regex patterns
 	archPattern := regexp.MustCompile(`(?i)architecture\s+(\w+)\s+of\s+(\w+)\s+is`)
 	packagePattern := regexp.MustCompile(`(?i)package\s+(body\s+)?(\w+)\s+is`)
+	entityPattern := regexp.MustCompile(`(?i)^\s*entity\s+(\w+)\s+is`)
 
 	isPackage := false
+	hasEntity := false
 	var fileType FileType
 
 	for scanner.Scan() {
@@ -147,6 +339,12 @@ func classifyVHDLFile(filePath string) (FileType, bool, error) {
 			continue
 		}
 
+		// Check for an entity, whose architecture may be in another file
+		if entityPattern.MatchString(line) {
+			hasEntity = true
+			continue
+		}
+
 		// Check for architecture
 		if matches := archPattern.FindStringSubmatch(line); matches != nil {
 			archName := strings.ToLower(matches[1])
@@ -163,5 +361,10 @@ func classifyVHDLFile(filePath string) (FileType, bool, error) {
 		return "", false, err
 	}
 
+	// A file declaring only an entity is compiled like RTL
+	if fileType == "" && hasEntity {
+		fileType = RTL
+	}
+
 	return fileType, isPackage, nil
 }
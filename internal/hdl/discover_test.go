@@ -3,6 +3,7 @@ package hdl
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -112,6 +113,12 @@ func TestClassifyVHDLFile(t *testing.T) {
 			expectedType: Behav,
 			isPackage:   false,
 		},
+		{
+			name:        "entity_only",
+			content:     "library ieee;\nentity my_entity is\n  port (clk : in std_logic);\nend entity;",
+			expectedType: RTL,
+			isPackage:   false,
+		},
 		{
 			name:        "package",
 			content:     "package my_package is\nend package;",
@@ -148,3 +155,119 @@ func TestClassifyVHDLFile(t *testing.T) {
 		})
 	}
 }
+
+func TestDiscoverFilesRecursive(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"rtl/core/alu.sv":    "module alu();",
+		"rtl/defs.vh":        "`define WIDTH 8",
+		"src/uart.vhdl":      "architecture rtl of uart is",
+		"src/uart_pkg.vhdl":  "package uart_pkg is",
+		"tb/top_test.sv":     "module top_test();",
+		"rtl/old/alu_old.sv": "module alu_old();",
+		".svn/pristine/x.v":  "module x();",
+		"doc/notes.txt":      "not HDL",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	hdlFiles, err := DiscoverFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+
+	expectedRTL := []string{"rtl/core/alu.sv", "rtl/defs.vh", "rtl/old/alu_old.sv", "src/uart.vhdl"}
+	if got := relPaths(tmpDir, hdlFiles.RTL); strings.Join(got, " ") != strings.Join(expectedRTL, " ") {
+		t.Errorf("RTL = %v, expected %v", got, expectedRTL)
+	}
+	if got := relPaths(tmpDir, hdlFiles.Behav); strings.Join(got, " ") != "tb/top_test.sv" {
+		t.Errorf("Behav = %v, expected [tb/top_test.sv]", got)
+	}
+	if got := relPaths(tmpDir, hdlFiles.Package); strings.Join(got, " ") != "src/uart_pkg.vhdl" {
+		t.Errorf("Package = %v, expected [src/uart_pkg.vhdl]", got)
+	}
+}
+
+func TestDiscoverFilesWithLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	testFiles := map[string]string{
+		"rtl/core/alu.sv":      "module alu();",
+		"rtl/core/alu_gold.sv": "module alu_gold();",
+		"rtl/old/alu_old.sv":   "module alu_old();",
+		"rtl/old/keep.sv":      "module keep();",
+		"rtl/.icwignore":       "old/\n",
+		"rtl/core/.icwignore":  "*_gold.sv\n",
+		"src/unused.v":         "module unused();",
+		"tb/top_tb.sv":         "module top_tb();",
+		"tb/scratch.sv":        "module scratch();",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+
+	layout := Layout{
+		Dirs:    []string{"rtl", "tb", "missing"},
+		Exclude: []string{"tb/scratch.sv"},
+	}
+	hdlFiles, err := DiscoverFilesWithLayout(tmpDir, layout)
+	if err != nil {
+		t.Fatalf("DiscoverFilesWithLayout failed: %v", err)
+	}
+
+	if got := relPaths(tmpDir, hdlFiles.RTL); strings.Join(got, " ") != "rtl/core/alu.sv" {
+		t.Errorf("RTL = %v, expected [rtl/core/alu.sv]", got)
+	}
+	if got := relPaths(tmpDir, hdlFiles.Behav); strings.Join(got, " ") != "tb/top_tb.sv" {
+		t.Errorf("Behav = %v, expected [tb/top_tb.sv]", got)
+	}
+}
+
+func TestIgnoreMatch(t *testing.T) {
+	ig := NewIgnore([]string{"*_old.v", "build/", "/top.v", "sim/**/*.log", "!keep_old.v"})
+	ig.Add("rtl", []string{"gen/*.sv"})
+
+	testCases := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"alu_old.v", false, true},
+		{"rtl/deep/alu_old.v", false, true},
+		{"keep_old.v", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"top.v", false, true},
+		{"rtl/top.v", false, false},
+		{"sim/a/b/run.log", false, true},
+		{"sim/run.log", false, true},
+		{"rtl/gen/regs.sv", false, true},
+		{"gen/regs.sv", false, false},
+		{"rtl/alu.v", false, false},
+	}
+
+	for _, tc := range testCases {
+		if got := ig.Match(tc.path, tc.isDir); got != tc.ignored {
+			t.Errorf("Match(%q, %v) = %v, expected %v", tc.path, tc.isDir, got, tc.ignored)
+		}
+	}
+}
+
+func relPaths(base string, paths []string) []string {
+	var rel []string
+	for _, p := range paths {
+		r, _ := filepath.Rel(base, p)
+		rel = append(rel, filepath.ToSlash(r))
+	}
+	return rel
+}
@@ -0,0 +1,67 @@
+package hdl
+
+import (
+	"sort"
+	"strings"
+)
+
+// Definition is a design unit together with the component that defines it
+type Definition struct {
+	Unit      Unit
+	Component string
+}
+
+// Duplicate is a design unit name defined in more than one file
+type Duplicate struct {
+	Name        string
+	Definitions []Definition
+}
+
+// Components returns the distinct components involved in a duplicate
+func (d Duplicate) Components() []string {
+	var comps []string
+	for _, def := range d.Definitions {
+		comps = appendUnique(comps, def.Component)
+	}
+	return comps
+}
+
+// duplicateKinds share one namespace in the simulator's work library
+var duplicateKinds = map[UnitKind]bool{UnitModule: true, UnitEntity: true, UnitPackage: true, UnitInterface: true}
+
+// Duplicates indexes the modules, entities, packages and interfaces of the
+// files each design uses in flow and returns the names defined more than
+// once. Names are compared case-insensitively, as mixed-language simulators
+// do. Candidate designs are not part of the workspace and are skipped.
+func Duplicates(designs []Design, flow Flow) []Duplicate {
+	index := make(map[string][]Definition)
+	for _, design := range designs {
+		if design.Candidate {
+			continue
+		}
+		for _, path := range design.Files.ForFlow(flow) {
+			info, ok := design.Files.infos[path]
+			if !ok {
+				continue
+			}
+			for _, unit := range info.Units {
+				if duplicateKinds[unit.Kind] {
+					key := strings.ToLower(unit.Name)
+					index[key] = append(index[key], Definition{Unit: unit, Component: design.Component})
+				}
+			}
+		}
+	}
+
+	var duplicates []Duplicate
+	for _, defs := range index {
+		if len(defs) > 1 {
+			duplicates = append(duplicates, Duplicate{Name: defs[0].Unit.Name, Definitions: defs})
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return strings.ToLower(duplicates[i].Name) < strings.ToLower(duplicates[j].Name)
+	})
+	return duplicates
+}
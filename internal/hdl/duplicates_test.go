@@ -0,0 +1,70 @@
+package hdl
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDuplicates(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	designs := []Design{
+		discoverDesign(t, tmpDir, "digital/cells_a", nil, map[string]string{
+			"sync_ff.v": "module sync_ff(); endmodule",
+			"pkg.sv":    "package common_pkg; endpackage",
+		}),
+		discoverDesign(t, tmpDir, "digital/cells_b", nil, map[string]string{
+			"sync_ff.sv": "module sync_ff(); endmodule",
+			"Common.vhd": "package COMMON_PKG is\nend package;",
+		}),
+		discoverDesign(t, tmpDir, "digital/top", nil, map[string]string{
+			"top.v":      "module top(); endmodule",
+			"top_gate.v": "module top(); endmodule", // Not part of rtl-sim
+		}),
+	}
+
+	candidate := discoverDesign(t, tmpDir, "digital/old", nil, map[string]string{
+		"sync_ff.v": "module sync_ff(); endmodule",
+	})
+	candidate.Candidate = true
+	designs = append(designs, candidate)
+
+	dups := Duplicates(designs, FlowRTLSim)
+	if len(dups) != 2 {
+		t.Fatalf("Expected 2 duplicates, got %+v", dups)
+	}
+
+	if !strings.EqualFold(dups[0].Name, "common_pkg") || strings.Join(dups[0].Components(), ",") != "digital/cells_a,digital/cells_b" {
+		t.Errorf("Unexpected duplicate: %+v", dups[0])
+	}
+	if dups[1].Name != "sync_ff" || len(dups[1].Definitions) != 2 {
+		t.Errorf("Unexpected duplicate: %+v", dups[1])
+	}
+	for _, def := range dups[1].Definitions {
+		if filepath.Base(filepath.Dir(def.Unit.File)) == "old" {
+			t.Errorf("Candidate design should be skipped: %+v", def)
+		}
+	}
+
+	// The gate netlist duplicates the RTL top in gate-sim only if both are used
+	if dups := Duplicates(designs[2:3], FlowGateSim); len(dups) != 0 {
+		t.Errorf("Expected no duplicates in gate-sim, got %+v", dups)
+	}
+}
+
+func TestGateNetlistNotScanned(t *testing.T) {
+	design := discoverDesign(t, t.TempDir(), "digital/top", nil, map[string]string{
+		"top.v":         "module top(); endmodule",
+		"top_netlist.v": "module top(); sky130_fd_sc_hd__dfxtp_1 u0(); endmodule",
+	})
+
+	if len(design.Files.Gate) != 1 {
+		t.Fatalf("Expected the netlist as gate file, got %v", design.Files.Gate)
+	}
+	for _, unit := range design.Files.Units {
+		if filepath.Base(unit.File) == "top_netlist.v" {
+			t.Errorf("Netlist should not be scanned, found unit %+v", unit)
+		}
+	}
+}
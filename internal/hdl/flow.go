@@ -0,0 +1,187 @@
+package hdl
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Flow is a build flow that uses a subset of the HDL files of a component
+type Flow string
+
+const (
+	FlowSynthesis Flow = "synthesis" // Synthesis of the RTL
+	FlowRTLSim    Flow = "rtl-sim"   // RTL simulation
+	FlowGateSim   Flow = "gate-sim"  // Simulation of gate-level netlists
+)
+
+// Flows lists all flows in the order they are shown
+var Flows = []Flow{FlowSynthesis, FlowRTLSim, FlowGateSim}
+
+// flowAliases are accepted alternative flow names
+var flowAliases = map[string]Flow{
+	"synth":          FlowSynthesis,
+	"sim":            FlowRTLSim,
+	"post-synth-sim": FlowGateSim,
+}
+
+// flowTypes are the file types each flow uses unless a rule says otherwise.
+// Gate-level simulation falls back to RTL for components without netlists.
+var flowTypes = map[Flow][]FileType{
+	FlowSynthesis: {Package, IP, RTL},
+	FlowRTLSim:    {Package, IP, Model, RTL, Behav},
+	FlowGateSim:   {Package, IP, Model, Gate, Behav},
+}
+
+// ParseFlow returns the flow with the given name or alias
+func ParseFlow(name string) (Flow, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, flow := range Flows {
+		if string(flow) == name {
+			return flow, nil
+		}
+	}
+	if flow, ok := flowAliases[name]; ok {
+		return flow, nil
+	}
+
+	names := make([]string, len(Flows))
+	for i, flow := range Flows {
+		names[i] = string(flow)
+	}
+	return "", fmt.Errorf("unknown flow %q (known flows: %s)", name, strings.Join(names, ", "))
+}
+
+// FlowRule includes or excludes files matching a pattern in one flow
+type FlowRule struct {
+	Include bool
+	Flow    Flow
+	Pattern string // .icwignore-style pattern relative to the component root
+}
+
+// ForFlow returns the files of a component used by the given flow in compile
+// order, packages first and testbenches last where they do not depend on each
+// other. Marker comments in the files are applied first, then the rules from
+// depend.config; the last matching rule wins.
+func (f *HDLFiles) ForFlow(flow Flow) []string {
+	types := flowTypes[flow]
+	if flow == FlowGateSim && len(f.Gate) == 0 {
+		types = flowTypes[FlowRTLSim]
+	}
+
+	used := make(map[FileType]bool)
+	for _, t := range types {
+		used[t] = true
+	}
+
+	var result []string
+	for _, group := range []struct {
+		fileType FileType
+		files    []string
+	}{
+		{Package, f.Package},
+		{IP, f.IP},
+		{Model, f.Model},
+		{Gate, f.Gate},
+		{RTL, f.RTL},
+		{Behav, f.Behav},
+	} {
+		for _, path := range group.files {
+			if f.included(path, flow, used[group.fileType]) {
+				result = append(result, path)
+			}
+		}
+	}
+	return orderPaths(result, f.infos)
+}
+
+// included applies the flow rules for a file to its default
+func (f *HDLFiles) included(path string, flow Flow, def bool) bool {
+	included := def
+	for _, rule := range f.markers[path] {
+		if rule.Flow == flow {
+			included = rule.Include
+		}
+	}
+
+	rel := relSlash(f.root, path)
+	for _, rule := range f.rules {
+		if rule.Flow == flow && NewIgnore([]string{rule.Pattern}).Match(rel, false) {
+			included = rule.Include
+		}
+	}
+	return included
+}
+
+// markerLines is how far into a file "icw:" markers are searched for, so
+// large netlists are not read completely
+const markerLines = 50
+
+var markerPattern = regexp.MustCompile(`(?i)^\s*(?://|--)\s*icw:\s*(.+)$`)
+
+// readMarkers reads "icw:" marker comments from the top of an HDL file:
+//
+//	// icw: gate                       (file type: rtl, behav, gate, model, ip, package)
+//	// icw: exclude synthesis          (flow rule)
+//	// icw: exclude-from-synthesis, include gate-sim
+//
+// VHDL files use -- instead of //. Unknown markers are ignored.
+func readMarkers(path string) (FileType, []FlowRule) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil
+	}
+	defer file.Close()
+
+	var fileType FileType
+	var rules []FlowRule
+
+	scanner := bufio.NewScanner(file)
+	for i := 0; i < markerLines && scanner.Scan(); i++ {
+		matches := markerPattern.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			continue
+		}
+
+		for _, marker := range strings.Split(matches[1], ",") {
+			fields := strings.Fields(strings.ToLower(marker))
+			if len(fields) == 0 {
+				continue
+			}
+
+			// exclude-from-<flow> and include-in-<flow> are one word
+			word := fields[0]
+			if rest, ok := strings.CutPrefix(word, "exclude-from-"); ok {
+				fields = []string{"exclude", rest}
+			} else if rest, ok := strings.CutPrefix(word, "include-in-"); ok {
+				fields = []string{"include", rest}
+			}
+
+			switch {
+			case len(fields) == 2 && (fields[0] == "include" || fields[0] == "exclude"):
+				if flow, err := ParseFlow(fields[1]); err == nil {
+					rules = append(rules, FlowRule{Include: fields[0] == "include", Flow: flow})
+				}
+			case len(fields) == 1:
+				if t, ok := parseFileType(fields[0]); ok {
+					fileType = t
+				}
+			}
+		}
+	}
+
+	return fileType, rules
+}
+
+// parseFileType returns the file type for a marker name
+func parseFileType(name string) (FileType, bool) {
+	switch name {
+	case "netlist":
+		return Gate, true
+	case string(Package), string(RTL), string(Behav), string(Gate), string(Model), string(IP):
+		return FileType(name), true
+	}
+	return "", false
+}
@@ -0,0 +1,117 @@
+package hdl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file %s: %v", name, err)
+		}
+	}
+}
+
+func TestClassifyGateModelIP(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"dig_top.v":          "module dig_top();",
+		"dig_top_cp3_gate.v": "module dig_top();",
+		"pll_model.sv":       "module pll();",
+		"models/adc.v":       "module adc();",
+		"ip/ram/ram.v":       "module ram();",
+		"post_layout.v":      "// icw: netlist\nmodule dig_top();",
+		"legacy_tb.v":        "// ICW: rtl\nmodule legacy();",
+		"top_tb.sv":          "module top_tb();",
+	})
+
+	files, err := DiscoverFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+
+	check := func(name string, got []string, expected string) {
+		if joined := strings.Join(relPaths(tmpDir, got), " "); joined != expected {
+			t.Errorf("%s = %q, expected %q", name, joined, expected)
+		}
+	}
+	check("RTL", files.RTL, "dig_top.v legacy_tb.v")
+	check("Gate", files.Gate, "dig_top_cp3_gate.v post_layout.v")
+	check("Model", files.Model, "models/adc.v pll_model.sv")
+	check("IP", files.IP, "ip/ram/ram.v")
+	check("Behav", files.Behav, "top_tb.sv")
+}
+
+func TestForFlow(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"pkg.vhd":            "package p is",
+		"dig_top.v":          "module dig_top();",
+		"dig_top_cp3_gate.v": "module dig_top();",
+		"pll_model.v":        "module pll();",
+		"top_tb.sv":          "module top_tb();",
+		"debug.v":            "// icw: exclude-from-synthesis\nmodule debug();",
+		"spare.v":            "module spare();",
+	})
+
+	layout := Layout{Rules: []FlowRule{
+		{Include: false, Flow: FlowRTLSim, Pattern: "spare.v"},
+		{Include: true, Flow: FlowGateSim, Pattern: "spare.v"},
+	}}
+	files, err := DiscoverFilesWithLayout(tmpDir, layout)
+	if err != nil {
+		t.Fatalf("DiscoverFilesWithLayout failed: %v", err)
+	}
+
+	expected := map[Flow]string{
+		FlowSynthesis: "pkg.vhd dig_top.v spare.v",
+		FlowRTLSim:    "pkg.vhd pll_model.v debug.v dig_top.v top_tb.sv",
+		FlowGateSim:   "pkg.vhd pll_model.v dig_top_cp3_gate.v spare.v top_tb.sv",
+	}
+	for flow, want := range expected {
+		if got := strings.Join(relPaths(tmpDir, files.ForFlow(flow)), " "); got != want {
+			t.Errorf("ForFlow(%s) = %q, expected %q", flow, got, want)
+		}
+	}
+}
+
+func TestForFlowGateSimFallback(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"spi.v":    "module spi();",
+		"spi_tb.v": "module spi_tb();",
+	})
+
+	files, err := DiscoverFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+
+	// Without a netlist the component is simulated from RTL
+	if got := strings.Join(relPaths(tmpDir, files.ForFlow(FlowGateSim)), " "); got != "spi.v spi_tb.v" {
+		t.Errorf("ForFlow(gate-sim) = %q, expected %q", got, "spi.v spi_tb.v")
+	}
+}
+
+func TestParseFlow(t *testing.T) {
+	for name, expected := range map[string]Flow{
+		"synthesis":      FlowSynthesis,
+		"RTL-Sim":        FlowRTLSim,
+		"post-synth-sim": FlowGateSim,
+	} {
+		flow, err := ParseFlow(name)
+		if err != nil || flow != expected {
+			t.Errorf("ParseFlow(%q) = %q, %v, expected %q", name, flow, err, expected)
+		}
+	}
+
+	if _, err := ParseFlow("layout"); err == nil {
+		t.Error("Expected error for unknown flow")
+	}
+}
@@ -0,0 +1,160 @@
+package hdl
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFile is the name of the per-directory file listing HDL files to skip
+const IgnoreFile = ".icwignore"
+
+// Ignore matches paths against gitignore-style patterns.
+//
+// Patterns without a slash match the file or directory name at any depth,
+// patterns with a slash match relative to the directory they are declared in.
+// A trailing slash matches directories only, a leading ! re-includes a path,
+// and ** matches across directories. The last matching pattern wins.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	base    string // Directory the pattern was declared in, relative to the component root
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewIgnore creates a matcher with patterns that apply to the whole component
+func NewIgnore(patterns []string) *Ignore {
+	ig := &Ignore{}
+	ig.Add("", patterns)
+	return ig
+}
+
+// Add adds patterns declared in the directory base (relative, "" for the root)
+func (ig *Ignore) Add(base string, patterns []string) {
+	base = strings.Trim(path.Clean("/"+base), "/")
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(pattern, "!") {
+			rule.negate = true
+			pattern = pattern[1:]
+		}
+		if strings.HasSuffix(pattern, "/") {
+			rule.dirOnly = true
+			pattern = strings.TrimRight(pattern, "/")
+		}
+		if pattern == "" {
+			continue
+		}
+
+		var expr string
+		if strings.Contains(pattern, "/") {
+			expr = "^" + globToRegexp(strings.TrimPrefix(pattern, "/")) + "$"
+		} else {
+			expr = "^(?:.*/)?" + globToRegexp(pattern) + "$"
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			continue
+		}
+		rule.pattern = re
+		ig.rules = append(ig.rules, rule)
+	}
+}
+
+// AddFile reads patterns from an ignore file in the directory base.
+// A missing file is not an error.
+func (ig *Ignore) AddFile(base, filePath string) error {
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	ig.Add(base, patterns)
+	return nil
+}
+
+// Match reports whether the slash-separated path rel (relative to the
+// component root) is ignored
+func (ig *Ignore) Match(rel string, isDir bool) bool {
+	rel = strings.Trim(path.Clean("/"+rel), "/")
+
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		sub := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			sub = rel[len(rule.base)+1:]
+		}
+
+		if rule.pattern.MatchString(sub) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// globToRegexp translates a glob pattern with ** support to a regular expression
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
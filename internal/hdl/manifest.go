@@ -0,0 +1,285 @@
+package hdl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ManifestVersion is the version of the manifest format written by WriteManifest
+const ManifestVersion = 1
+
+// Manifest records the HDL files of a workspace with their content hashes,
+// so that a later state can be compared against it
+type Manifest struct {
+	Version    int                 `json:"version"`
+	Flow       Flow                `json:"flow,omitempty"` // Empty if all files are recorded
+	Components []ManifestComponent `json:"components"`     // Dependencies first
+}
+
+// ManifestComponent is the state of one component in a manifest
+type ManifestComponent struct {
+	Name     string         `json:"name"`
+	Branch   string         `json:"branch"`
+	Revision int            `json:"revision,omitempty"` // SVN base revision; 0 if unknown
+	Deps     []string       `json:"deps,omitempty"`     // Names of the direct dependencies
+	Files    []ManifestFile `json:"files"`              // Sorted by path
+}
+
+// ManifestFile is a file and the SHA-256 of its content
+type ManifestFile struct {
+	Path   string `json:"path"` // Relative to the component root
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestConfig is hashed along with the HDL files, since its include
+// directories, defines and flow rules change how the files compile
+const ManifestConfig = "depend.config"
+
+// NewManifestComponent hashes the files of a component used by flow, or all
+// of its files if flow is empty, together with its depend.config and the
+// files in its include directories, which may belong to a dependency
+func NewManifestComponent(name string, files *HDLFiles, flow Flow, incdirs []string) (ManifestComponent, error) {
+	comp := ManifestComponent{Name: name, Files: []ManifestFile{}}
+
+	paths := files.All()
+	if flow != "" {
+		paths = files.ForFlow(flow)
+	}
+	if config := filepath.Join(files.root, ManifestConfig); fileExists(config) {
+		paths = append(paths, config)
+	}
+	included, err := includeFiles(incdirs)
+	if err != nil {
+		return comp, err
+	}
+	for _, path := range included {
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
+	for _, path := range paths {
+		sum, err := HashFile(path)
+		if err != nil {
+			return comp, err
+		}
+		comp.Files = append(comp.Files, ManifestFile{Path: relSlash(files.root, path), SHA256: sum})
+	}
+	sort.Slice(comp.Files, func(i, j int) bool {
+		return comp.Files[i].Path < comp.Files[j].Path
+	})
+
+	return comp, nil
+}
+
+// includeFiles returns the regular files directly in the include
+// directories; directories that do not exist are skipped
+func includeFiles(incdirs []string) ([]string, error) {
+	var paths []string
+	for _, dir := range incdirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return paths, nil
+}
+
+// HashFile returns the hex encoded SHA-256 of a file's content
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteManifest writes a manifest as indented JSON
+func WriteManifest(w io.Writer, m *Manifest) error {
+	m.Version = ManifestVersion
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadManifest reads a manifest written by WriteManifest
+func ReadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d in %s", m.Version, path)
+	}
+	return &m, nil
+}
+
+// ChangeStatus tells how a component or file differs between two manifests
+type ChangeStatus string
+
+const (
+	Added    ChangeStatus = "added"
+	Removed  ChangeStatus = "removed"
+	Modified ChangeStatus = "modified"
+	Affected ChangeStatus = "affected" // Unchanged, but a dependency changed
+)
+
+// FileChange is a file that differs between two manifests
+type FileChange struct {
+	Path   string       `json:"path"`
+	Status ChangeStatus `json:"status"`
+}
+
+// ComponentChange is a component that must be recompiled
+type ComponentChange struct {
+	Name        string       `json:"name"`
+	Status      ChangeStatus `json:"status"`
+	OldRevision int          `json:"old_revision,omitempty"`
+	NewRevision int          `json:"new_revision,omitempty"`
+	Files       []FileChange `json:"files,omitempty"`
+	ChangedDeps []string     `json:"changed_deps,omitempty"` // For affected components
+}
+
+// DiffManifests compares two manifests and returns the changed components in
+// the order of the new manifest, followed by the removed ones. Components
+// whose own files are unchanged are reported as affected when one of their
+// transitive dependencies changed. A new revision alone is not a change.
+func DiffManifests(old, new *Manifest) []ComponentChange {
+	oldComps := make(map[string]ManifestComponent)
+	for _, comp := range old.Components {
+		oldComps[comp.Name] = comp
+	}
+
+	var changes []ComponentChange
+	changed := make(map[string]bool)
+	newComps := make(map[string]ManifestComponent)
+
+	for _, comp := range new.Components {
+		newComps[comp.Name] = comp
+
+		prev, ok := oldComps[comp.Name]
+		if !ok {
+			changes = append(changes, ComponentChange{Name: comp.Name, Status: Added, NewRevision: comp.Revision, Files: fileChanges(nil, comp.Files)})
+			changed[comp.Name] = true
+			continue
+		}
+
+		if files := fileChanges(prev.Files, comp.Files); len(files) > 0 {
+			changes = append(changes, ComponentChange{
+				Name:        comp.Name,
+				Status:      Modified,
+				OldRevision: prev.Revision,
+				NewRevision: comp.Revision,
+				Files:       files,
+			})
+			changed[comp.Name] = true
+		}
+	}
+
+	for _, comp := range old.Components {
+		if _, ok := newComps[comp.Name]; !ok {
+			changes = append(changes, ComponentChange{Name: comp.Name, Status: Removed, OldRevision: comp.Revision})
+			changed[comp.Name] = true
+		}
+	}
+
+	// Components are listed dependencies first, but affected ones are placed
+	// after all changes so the list of changes stays easy to read
+	for _, comp := range new.Components {
+		if changed[comp.Name] {
+			continue
+		}
+		if deps := changedDeps(comp.Name, newComps, changed); len(deps) > 0 {
+			changes = append(changes, ComponentChange{Name: comp.Name, Status: Affected, NewRevision: comp.Revision, ChangedDeps: deps})
+		}
+	}
+
+	return changes
+}
+
+// fileChanges compares two file lists sorted by path
+func fileChanges(old, new []ManifestFile) []FileChange {
+	oldSums := make(map[string]string)
+	for _, file := range old {
+		oldSums[file.Path] = file.SHA256
+	}
+
+	var changes []FileChange
+	seen := make(map[string]bool)
+	for _, file := range new {
+		seen[file.Path] = true
+		sum, ok := oldSums[file.Path]
+		if !ok {
+			changes = append(changes, FileChange{Path: file.Path, Status: Added})
+		} else if sum != file.SHA256 {
+			changes = append(changes, FileChange{Path: file.Path, Status: Modified})
+		}
+	}
+	for _, file := range old {
+		if !seen[file.Path] {
+			changes = append(changes, FileChange{Path: file.Path, Status: Removed})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// changedDeps returns the sorted transitive dependencies of a component that changed
+func changedDeps(name string, comps map[string]ManifestComponent, changed map[string]bool) []string {
+	var deps []string
+	visited := map[string]bool{name: true}
+	queue := append([]string(nil), comps[name].Deps...)
+	for len(queue) > 0 {
+		dep := queue[0]
+		queue = queue[1:]
+		if visited[dep] {
+			continue
+		}
+		visited[dep] = true
+		if changed[dep] {
+			deps = append(deps, dep)
+		}
+		queue = append(queue, comps[dep].Deps...)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// fileExists reports whether path is an existing regular file
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,144 @@
+package hdl
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewManifestComponent(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"rtl/top.v":      "module top(); endmodule",
+		"tb/top_tb.sv":   "module top_tb(); top dut(); endmodule",
+		"depend.config":  "define WIDTH \"8\"",
+		"doc/readme.txt": "not HDL",
+	})
+
+	files, err := DiscoverFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+
+	comp, err := NewManifestComponent("digital/top", files, "", nil)
+	if err != nil {
+		t.Fatalf("NewManifestComponent failed: %v", err)
+	}
+	var paths []string
+	for _, file := range comp.Files {
+		paths = append(paths, file.Path)
+	}
+	expected := []string{"depend.config", "rtl/top.v", "tb/top_tb.sv"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected files %v, got %v", expected, paths)
+	}
+	sum, _ := HashFile(filepath.Join(tmpDir, "rtl", "top.v"))
+	if comp.Files[1].SHA256 != sum || len(sum) != 64 {
+		t.Errorf("Unexpected hash %q", comp.Files[1].SHA256)
+	}
+
+	synth, err := NewManifestComponent("digital/top", files, FlowSynthesis, nil)
+	if err != nil {
+		t.Fatalf("NewManifestComponent failed: %v", err)
+	}
+	if len(synth.Files) != 2 || synth.Files[1].Path != "rtl/top.v" {
+		t.Errorf("Synthesis manifest should leave out the testbench: %+v", synth.Files)
+	}
+}
+
+func TestNewManifestComponentIncdirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"top/rtl/top.v":        "`include \"defs.vh\"\nmodule top(); endmodule",
+		"top/inc/local.vh":     "`define LOCAL",
+		"lib/include/defs.vh":  "`define WIDTH 8",
+		"lib/include/regs.inc": "`define REGS 4",
+	})
+
+	files, err := DiscoverFiles(filepath.Join(tmpDir, "top"))
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+	incdirs := []string{
+		filepath.Join(tmpDir, "top", "inc"),
+		filepath.Join(tmpDir, "lib", "include"),
+		filepath.Join(tmpDir, "lib", "missing"),
+	}
+
+	comp, err := NewManifestComponent("digital/top", files, FlowRTLSim, incdirs)
+	if err != nil {
+		t.Fatalf("NewManifestComponent failed: %v", err)
+	}
+	var paths []string
+	for _, file := range comp.Files {
+		paths = append(paths, file.Path)
+	}
+	expected := []string{"../lib/include/defs.vh", "../lib/include/regs.inc", "inc/local.vh", "rtl/top.v"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected files %v, got %v", expected, paths)
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	m := &Manifest{Flow: FlowRTLSim, Components: []ManifestComponent{
+		{Name: "digital/lib", Branch: "trunk", Revision: 42, Files: []ManifestFile{{Path: "lib.v", SHA256: "ab"}}},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteManifest(&buf, m); err != nil {
+		t.Fatalf("WriteManifest failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	os.WriteFile(path, buf.Bytes(), 0644)
+
+	read, err := ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest failed: %v", err)
+	}
+	if !reflect.DeepEqual(read, m) {
+		t.Errorf("Round trip changed the manifest:\n%+v\n%+v", m, read)
+	}
+
+	os.WriteFile(path, []byte(`{"version": 99, "components": []}`), 0644)
+	if _, err := ReadManifest(path); err == nil {
+		t.Error("Expected error for unsupported version")
+	}
+}
+
+func TestDiffManifests(t *testing.T) {
+	old := &Manifest{Components: []ManifestComponent{
+		{Name: "digital/lib", Revision: 10, Files: []ManifestFile{{"fifo.sv", "1"}, {"old.v", "2"}, {"sync.v", "3"}}},
+		{Name: "digital/legacy", Revision: 10, Files: []ManifestFile{{"x.v", "4"}}},
+		{Name: "digital/spi", Revision: 10, Deps: []string{"digital/lib"}, Files: []ManifestFile{{"spi.v", "5"}}},
+		{Name: "digital/top", Revision: 10, Deps: []string{"digital/spi"}, Files: []ManifestFile{{"top.v", "6"}}},
+		{Name: "digital/pads", Revision: 10, Files: []ManifestFile{{"pads.v", "7"}}},
+	}}
+	new := &Manifest{Components: []ManifestComponent{
+		{Name: "digital/lib", Revision: 12, Files: []ManifestFile{{"fifo.sv", "1b"}, {"new.v", "8"}, {"sync.v", "3"}}},
+		{Name: "digital/uart", Revision: 12, Files: []ManifestFile{{"uart.v", "9"}}},
+		{Name: "digital/spi", Revision: 12, Deps: []string{"digital/lib"}, Files: []ManifestFile{{"spi.v", "5"}}},
+		{Name: "digital/top", Revision: 12, Deps: []string{"digital/spi", "digital/uart"}, Files: []ManifestFile{{"top.v", "6"}}},
+		{Name: "digital/pads", Revision: 12, Files: []ManifestFile{{"pads.v", "7"}}},
+	}}
+
+	changes := DiffManifests(old, new)
+
+	expected := []ComponentChange{
+		{Name: "digital/lib", Status: Modified, OldRevision: 10, NewRevision: 12, Files: []FileChange{
+			{"fifo.sv", Modified}, {"new.v", Added}, {"old.v", Removed},
+		}},
+		{Name: "digital/uart", Status: Added, NewRevision: 12, Files: []FileChange{{"uart.v", Added}}},
+		{Name: "digital/legacy", Status: Removed, OldRevision: 10},
+		{Name: "digital/spi", Status: Affected, NewRevision: 12, ChangedDeps: []string{"digital/lib"}},
+		{Name: "digital/top", Status: Affected, NewRevision: 12, ChangedDeps: []string{"digital/lib", "digital/uart"}},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Unexpected changes:\n got %+v\nwant %+v", changes, expected)
+	}
+
+	if changes := DiffManifests(new, new); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
@@ -0,0 +1,137 @@
+package hdl
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SortFiles orders files so that each file comes after the files defining
+// the units it uses and the files it includes. Files without an ordering
+// constraint keep their relative input order, so the result is stable.
+// Dependency cycles are broken at the earliest file in input order.
+func SortFiles(infos []*FileInfo) []*FileInfo {
+	// Index where every unit and include target is defined
+	definedIn := make(map[string]int)
+	byName := make(map[string]int)
+	for i, info := range infos {
+		for _, unit := range info.Units {
+			if _, ok := definedIn[unit.Name]; !ok {
+				definedIn[unit.Name] = i
+			}
+		}
+		base := filepath.Base(info.Path)
+		if _, ok := byName[base]; !ok {
+			byName[base] = i
+		}
+	}
+
+	// deps[i] holds the files that must be compiled before file i
+	deps := make([][]int, len(infos))
+	for i, info := range infos {
+		add := func(j int) {
+			if j != i {
+				deps[i] = append(deps[i], j)
+			}
+		}
+		for _, name := range info.Uses {
+			if j, ok := lookupUnit(definedIn, name); ok {
+				add(j)
+			}
+		}
+		for _, include := range info.Includes {
+			if j, ok := byName[filepath.Base(include)]; ok {
+				add(j)
+			}
+		}
+	}
+
+	// Depth-first post-order in input order gives a deterministic
+	// topological order; a file on the current path is a cycle and skipped
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(infos))
+	var sorted []*FileInfo
+
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != unvisited {
+			return
+		}
+		state[i] = visiting
+		for _, j := range deps[i] {
+			visit(j)
+		}
+		state[i] = done
+		sorted = append(sorted, infos[i])
+	}
+
+	for i := range infos {
+		visit(i)
+	}
+	return sorted
+}
+
+// lookupUnit finds a unit by name. VHDL names are stored in lower case, so
+// a Verilog reference to a VHDL entity is matched case-insensitively.
+func lookupUnit(definedIn map[string]int, name string) (int, bool) {
+	if i, ok := definedIn[name]; ok {
+		return i, true
+	}
+	i, ok := definedIn[strings.ToLower(name)]
+	return i, ok
+}
+
+// orderPaths sorts paths into compile order using already scanned files
+func orderPaths(paths []string, infos map[string]*FileInfo) []string {
+	list := make([]*FileInfo, len(paths))
+	for i, path := range paths {
+		if info, ok := infos[path]; ok {
+			list[i] = info
+		} else {
+			list[i] = &FileInfo{Path: path}
+		}
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for _, info := range SortFiles(list) {
+		sorted = append(sorted, info.Path)
+	}
+	return sorted
+}
+
+// FlowFiles returns the files of several components used by a flow in
+// compile order. Components should be given with dependencies first, which
+// then also decides the order of files that do not depend on each other.
+func FlowFiles(components []*HDLFiles, flow Flow) []string {
+	var paths []string
+	infos := make(map[string]*FileInfo)
+	for _, files := range components {
+		paths = append(paths, files.ForFlow(flow)...)
+		for path, info := range files.infos {
+			infos[path] = info
+		}
+	}
+	return orderPaths(paths, infos)
+}
+
+// CompileOrder scans files and returns them in compile order.
+// Files that cannot be read are kept in their input position.
+func CompileOrder(paths []string) []string {
+	infos := make([]*FileInfo, len(paths))
+	for i, path := range paths {
+		info, err := ScanFile(path)
+		if err != nil {
+			info = &FileInfo{Path: path}
+		}
+		infos[i] = info
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for _, info := range SortFiles(infos) {
+		sorted = append(sorted, info.Path)
+	}
+	return sorted
+}
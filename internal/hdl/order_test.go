@@ -0,0 +1,84 @@
+package hdl
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func baseNames(infos []*FileInfo) string {
+	var names []string
+	for _, info := range infos {
+		names = append(names, filepath.Base(info.Path))
+	}
+	return strings.Join(names, " ")
+}
+
+func TestSortFiles(t *testing.T) {
+	infos := []*FileInfo{
+		{Path: "top.v", Units: []Unit{{Name: "top"}}, Uses: []string{"alu", "bus_pkg"}},
+		{Path: "alu.sv", Units: []Unit{{Name: "alu"}}, Uses: []string{"alu_pkg"}, Includes: []string{"inc/defs.svh"}},
+		{Path: "alu_pkg.sv", Units: []Unit{{Name: "alu_pkg"}}, Uses: []string{"bus_pkg"}},
+		{Path: "bus_pkg.sv", Units: []Unit{{Name: "bus_pkg"}}},
+		{Path: "defs.svh"},
+		{Path: "unrelated.v", Units: []Unit{{Name: "unrelated"}}, Uses: []string{"std_cell"}},
+	}
+
+	expected := "bus_pkg.sv alu_pkg.sv defs.svh alu.sv top.v unrelated.v"
+	if got := baseNames(SortFiles(infos)); got != expected {
+		t.Errorf("SortFiles = %q, expected %q", got, expected)
+	}
+}
+
+func TestSortFilesStable(t *testing.T) {
+	// Files without dependencies keep their input order
+	infos := []*FileInfo{
+		{Path: "c.v", Units: []Unit{{Name: "c"}}},
+		{Path: "a.v", Units: []Unit{{Name: "a"}}},
+		{Path: "b.v", Units: []Unit{{Name: "b"}}},
+	}
+	if got := baseNames(SortFiles(infos)); got != "c.v a.v b.v" {
+		t.Errorf("SortFiles = %q, expected %q", got, "c.v a.v b.v")
+	}
+}
+
+func TestSortFilesCycle(t *testing.T) {
+	infos := []*FileInfo{
+		{Path: "a.v", Units: []Unit{{Name: "a"}}, Uses: []string{"b"}},
+		{Path: "b.v", Units: []Unit{{Name: "b"}}, Uses: []string{"a"}},
+		{Path: "c.v", Units: []Unit{{Name: "c"}}, Uses: []string{"a"}},
+	}
+	if got := baseNames(SortFiles(infos)); got != "b.v a.v c.v" {
+		t.Errorf("SortFiles = %q, expected %q", got, "b.v a.v c.v")
+	}
+}
+
+func TestSortFilesMixedLanguage(t *testing.T) {
+	// Verilog instantiating a VHDL entity, whose name is stored in lower case
+	infos := []*FileInfo{
+		{Path: "top.v", Units: []Unit{{Name: "top"}}, Uses: []string{"Uart"}},
+		{Path: "uart.vhd", Units: []Unit{{Name: "uart", Kind: UnitEntity}}},
+	}
+	if got := baseNames(SortFiles(infos)); got != "uart.vhd top.v" {
+		t.Errorf("SortFiles = %q, expected %q", got, "uart.vhd top.v")
+	}
+}
+
+func TestCompileOrderVHDLPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"a_pkg.vhd": "use work.c_pkg.all;\npackage a_pkg is\nend package;",
+		"b_pkg.vhd": "package b_pkg is\nend package;",
+		"c_pkg.vhd": "use work.b_pkg.all;\npackage c_pkg is\nend package;",
+	})
+
+	files, err := DiscoverFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+
+	expected := "b_pkg.vhd c_pkg.vhd a_pkg.vhd"
+	if got := strings.Join(relPaths(tmpDir, files.Package), " "); got != expected {
+		t.Errorf("Package = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,294 @@
+package hdl
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// UnitKind is the kind of a design unit
+type UnitKind string
+
+const (
+	UnitModule    UnitKind = "module"
+	UnitInterface UnitKind = "interface"
+	UnitPackage   UnitKind = "package"
+	UnitEntity    UnitKind = "entity"
+	UnitProgram   UnitKind = "program"
+	UnitClass     UnitKind = "class"
+)
+
+// Unit is a design unit defined in an HDL file
+type Unit struct {
+	Name string
+	Kind UnitKind
+	File string
+}
+
+// FileInfo holds the design units a file defines and the names it refers to
+type FileInfo struct {
+	Path     string
+	Units    []Unit   // Units defined in the file
+	Uses     []string // Packages, entities and modules the file refers to
+	Includes []string // Files included with `include, as written
+
+	// Testbench is set for Verilog files with testbench constructs: a
+	// program block, an initial block calling $finish or $stop, or UVM
+	Testbench bool
+}
+
+// HasUnit reports whether the file defines a unit of the given kind
+func (info *FileInfo) HasUnit(kind UnitKind) bool {
+	for _, unit := range info.Units {
+		if unit.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// isVHDL reports whether path is a VHDL file
+func isVHDL(path string) bool {
+	return vhdlExts[strings.ToLower(filepath.Ext(path))]
+}
+
+// ScanFile reads an HDL file and extracts its design units and references.
+// VHDL names are returned in lower case as VHDL is case-insensitive.
+func ScanFile(path string) (*FileInfo, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &FileInfo{Path: path}
+	if isVHDL(path) {
+		scanVHDL(info, stripComments(string(content), true))
+	} else {
+		scanVerilog(info, stripComments(string(content), false))
+	}
+
+	info.Uses = uniqueSorted(info.Uses)
+	info.Includes = uniqueSorted(info.Includes)
+	return info, nil
+}
+
+// stripComments removes comments and the contents of string literals, keeping
+// line breaks. Include file names are kept, as `include needs them.
+func stripComments(text string, vhdl bool) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case vhdl && strings.HasPrefix(text[i:], "--"), !vhdl && strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			if i < len(text) {
+				b.WriteByte('\n')
+			}
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				end = len(text) - i - 2
+			}
+			comment := text[i : i+2+end]
+			b.WriteString(strings.Repeat("\n", strings.Count(comment, "\n")))
+			i += 2 + end + 1
+		case vhdl && c == '\'' && i+2 < len(text) && text[i+2] == '\'':
+			// VHDL character literal such as '"'
+			b.WriteString("' '")
+			i += 2
+		case c == '"':
+			j := i + 1
+			for j < len(text) && text[j] != '"' && text[j] != '\n' {
+				if text[j] == '\\' && !vhdl {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(text))
+
+			// Keep the name in `include "file", blank other literals
+			if strings.HasSuffix(strings.TrimRight(b.String(), " \t"), "`include") {
+				b.WriteString(`"` + text[i+1:j] + `"`)
+			} else {
+				b.WriteString(`""`)
+			}
+			i = j
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+var (
+	vhdlEntityPattern       = regexp.MustCompile(`(?i)\bentity\s+(\w+)\s+is\b`)
+	vhdlPackagePattern      = regexp.MustCompile(`(?i)\bpackage\s+(\w+)\s+is\b`)
+	vhdlPackageBodyPattern  = regexp.MustCompile(`(?i)\bpackage\s+body\s+(\w+)\s+is\b`)
+	vhdlArchitecturePattern = regexp.MustCompile(`(?i)\b(?:architecture|configuration)\s+\w+\s+of\s+(\w+)\s+is\b`)
+	vhdlUsePattern          = regexp.MustCompile(`(?i)\buse\s+(\w+)\.(\w+)`)
+	vhdlEntityInstPattern   = regexp.MustCompile(`(?i):\s*entity\s+(\w+)\.(\w+)`)
+	vhdlCompInstPattern     = regexp.MustCompile(`(?i)\w+\s*:\s*(?:component\s+)?(\w+)\s+(?:generic|port)\s+map\b`)
+)
+
+// vhdlStandardLibraries are provided by the simulator
+var vhdlStandardLibraries = map[string]bool{"ieee": true, "std": true}
+
+func scanVHDL(info *FileInfo, text string) {
+	for _, m := range vhdlEntityPattern.FindAllStringSubmatch(text, -1) {
+		info.Units = append(info.Units, Unit{Name: strings.ToLower(m[1]), Kind: UnitEntity, File: info.Path})
+	}
+	for _, m := range vhdlPackagePattern.FindAllStringSubmatch(text, -1) {
+		if strings.EqualFold(m[1], "body") {
+			continue
+		}
+		info.Units = append(info.Units, Unit{Name: strings.ToLower(m[1]), Kind: UnitPackage, File: info.Path})
+	}
+
+	for _, m := range vhdlPackageBodyPattern.FindAllStringSubmatch(text, -1) {
+		info.Uses = append(info.Uses, strings.ToLower(m[1]))
+	}
+	for _, m := range vhdlArchitecturePattern.FindAllStringSubmatch(text, -1) {
+		info.Uses = append(info.Uses, strings.ToLower(m[1]))
+	}
+	for _, m := range vhdlUsePattern.FindAllStringSubmatch(text, -1) {
+		if !vhdlStandardLibraries[strings.ToLower(m[1])] {
+			info.Uses = append(info.Uses, strings.ToLower(m[2]))
+		}
+	}
+	for _, m := range vhdlEntityInstPattern.FindAllStringSubmatch(text, -1) {
+		info.Uses = append(info.Uses, strings.ToLower(m[2]))
+	}
+	for _, m := range vhdlCompInstPattern.FindAllStringSubmatch(text, -1) {
+		info.Uses = append(info.Uses, strings.ToLower(m[1]))
+	}
+
+	info.Uses = withoutOwnUnits(info)
+}
+
+var (
+	svUnitPattern     = regexp.MustCompile(`\b(module|macromodule|interface|package|program|class)\s+(?:automatic\s+|static\s+)?([A-Za-z_]\w*)`)
+	svInitialPattern  = regexp.MustCompile(`\binitial\b`)
+	svFinishPattern   = regexp.MustCompile(`\$(?:finish|stop)\b`)
+	svUVMPattern      = regexp.MustCompile("\\buvm_pkg::|`include\\s+\"uvm_macros\\.svh\"")
+	svImportPattern   = regexp.MustCompile(`\b([A-Za-z_]\w*)::`)
+	svIncludePattern  = regexp.MustCompile("`include\\s+\"([^\"]+)\"")
+	svInstancePattern = regexp.MustCompile(`(?:^|;|\bbegin\b|\bend\b|\belse\b|\bgenerate\b|\)|:)\s*([A-Za-z_]\w*)\s*(?:#\s*\((?:[^()]|\([^()]*\))*\))?\s*([A-Za-z_]\w*)\s*(?:\[[^\]]*\]\s*)?\(`)
+)
+
+// svKeywords cannot start a module instantiation
+var svKeywords = makeSet(`always always_comb always_ff always_latch and assert assign assume automatic
+begin bind bit buf bufif0 bufif1 byte case casex casez class clocking const constraint cover covergroup
+deassign default defparam disable do else end endcase endfunction endgenerate endmodule endtask enum
+event extern final for force foreach forever fork function generate genvar if iff import initial inout
+input int integer interface join join_any join_none localparam logic longint macromodule modport module
+nand nor not notif0 notif1 or output package parameter primitive priority program property pulldown
+pullup real realtime reg release repeat return sequence shortint signed specify static string struct
+supply0 supply1 task time tri tri0 tri1 triand trior type typedef union unique unsigned var virtual
+void wait wand while wire wor xnor xor`)
+
+func scanVerilog(info *FileInfo, text string) {
+	for _, m := range svUnitPattern.FindAllStringSubmatchIndex(text, -1) {
+		keyword, name := text[m[2]:m[3]], text[m[4]:m[5]]
+
+		// "typedef class x;" is a forward declaration and
+		// "virtual interface x" a variable type, not definitions
+		prev := previousWord(text, m[0])
+		if prev == "typedef" || (keyword == "interface" && prev == "virtual") {
+			continue
+		}
+
+		kind := UnitModule
+		switch keyword {
+		case "interface":
+			kind = UnitInterface
+		case "package":
+			kind = UnitPackage
+		case "program":
+			kind = UnitProgram
+		case "class":
+			kind = UnitClass
+		}
+		info.Units = append(info.Units, Unit{Name: name, Kind: kind, File: info.Path})
+	}
+
+	info.Testbench = info.HasUnit(UnitProgram) ||
+		(svInitialPattern.MatchString(text) && svFinishPattern.MatchString(text)) ||
+		svUVMPattern.MatchString(text)
+
+	for _, m := range svImportPattern.FindAllStringSubmatch(text, -1) {
+		if m[1] != "std" && m[1] != "$unit" {
+			info.Uses = append(info.Uses, m[1])
+		}
+	}
+	for _, m := range svIncludePattern.FindAllStringSubmatch(text, -1) {
+		info.Includes = append(info.Includes, m[1])
+	}
+	for _, m := range svInstancePattern.FindAllStringSubmatch(text, -1) {
+		if !svKeywords[m[1]] && !svKeywords[m[2]] {
+			info.Uses = append(info.Uses, m[1])
+		}
+	}
+
+	info.Uses = withoutOwnUnits(info)
+}
+
+// previousWord returns the identifier that ends before position end, skipping whitespace
+func previousWord(text string, end int) string {
+	i := end
+	for i > 0 && strings.IndexByte(" \t\r\n", text[i-1]) >= 0 {
+		i--
+	}
+	start := i
+	for start > 0 && isWordChar(text[start-1]) {
+		start--
+	}
+	return text[start:i]
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// withoutOwnUnits drops references to units the file defines itself
+func withoutOwnUnits(info *FileInfo) []string {
+	own := make(map[string]bool)
+	for _, unit := range info.Units {
+		own[unit.Name] = true
+	}
+
+	var uses []string
+	for _, name := range info.Uses {
+		if !own[name] {
+			uses = append(uses, name)
+		}
+	}
+	return uses
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Strings(values)
+	result := values[:1]
+	for _, v := range values[1:] {
+		if v != result[len(result)-1] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func makeSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
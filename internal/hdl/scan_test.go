@@ -0,0 +1,184 @@
+package hdl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func scanString(t *testing.T, name, content string) *FileInfo {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	info, err := ScanFile(path)
+	if err != nil {
+		t.Fatalf("ScanFile failed: %v", err)
+	}
+	return info
+}
+
+func unitNames(units []Unit) string {
+	var names []string
+	for _, unit := range units {
+		names = append(names, string(unit.Kind)+":"+unit.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestScanVHDL(t *testing.T) {
+	info := scanString(t, "uart.vhd", `library ieee;
+use ieee.std_logic_1164.all;
+use work.uart_pkg.all;
+library common;
+use common.sync_pkg.all;
+
+-- entity commented_out is
+entity UART is
+  port (clk : in std_logic);
+end entity;
+
+architecture rtl of uart is
+  signal s : character := '"';
+begin
+  u_fifo : entity work.fifo port map (clk => clk);
+  u_sync : sync_ff generic map (N => 2) port map (clk => clk);
+end architecture;
+`)
+
+	if got := unitNames(info.Units); got != "entity:uart" {
+		t.Errorf("Units = %q, expected %q", got, "entity:uart")
+	}
+	if got := strings.Join(info.Uses, " "); got != "fifo sync_ff sync_pkg uart_pkg" {
+		t.Errorf("Uses = %q, expected %q", got, "fifo sync_ff sync_pkg uart_pkg")
+	}
+}
+
+func TestScanVHDLPackageBody(t *testing.T) {
+	info := scanString(t, "pkg.vhd", `package uart_pkg is
+  constant W : integer := 8;
+end package;
+
+package body uart_pkg is
+end package body;
+`)
+
+	if got := unitNames(info.Units); got != "package:uart_pkg" {
+		t.Errorf("Units = %q, expected %q", got, "package:uart_pkg")
+	}
+	if len(info.Uses) != 0 {
+		t.Errorf("Expected no uses, got %v", info.Uses)
+	}
+}
+
+func TestScanVerilog(t *testing.T) {
+	info := scanString(t, "top.sv", "`include \"defs.svh\"\n"+`
+import bus_pkg::*;
+
+// module commented_out;
+/* spi_master u_hidden (.clk(clk)); */
+module top #(parameter W = 8) (input logic clk);
+  logic [W-1:0] data;
+  string msg = "fifo u_str (";
+
+  spi_master #(.W(W), .D(2)) u_spi (.clk(clk));
+  sync_ff u_sync [3:0] (.clk(clk));
+  assign data = bus_pkg::ZERO;
+
+  always_ff @(posedge clk) begin
+    if (data) data <= '0;
+  end
+endmodule
+
+interface bus_if (input logic clk);
+endinterface
+`)
+
+	if got := unitNames(info.Units); got != "module:top interface:bus_if" {
+		t.Errorf("Units = %q, expected %q", got, "module:top interface:bus_if")
+	}
+	if got := strings.Join(info.Uses, " "); got != "bus_pkg spi_master sync_ff" {
+		t.Errorf("Uses = %q, expected %q", got, "bus_pkg spi_master sync_ff")
+	}
+	if got := strings.Join(info.Includes, " "); got != "defs.svh" {
+		t.Errorf("Includes = %q, expected %q", got, "defs.svh")
+	}
+}
+
+func TestScanVerilogUnits(t *testing.T) {
+	info := scanString(t, "env.sv", `
+typedef class driver;
+
+package env_pkg;
+  virtual class base;
+  endclass
+
+  class driver extends base;
+    virtual interface bus_if vif;
+  endclass
+endpackage
+
+program test;
+endprogram
+`)
+
+	expected := "package:env_pkg class:base class:driver program:test"
+	if got := unitNames(info.Units); got != expected {
+		t.Errorf("Units = %q, expected %q", got, expected)
+	}
+	if !info.Testbench {
+		t.Error("Expected a program block to mark a testbench")
+	}
+}
+
+func TestScanVerilogTestbench(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		testbench bool
+	}{
+		{"finish", "module t;\n initial begin\n #10 $finish;\n end\nendmodule", true},
+		{"uvm", "module t;\n import uvm_pkg::*;\nendmodule", true},
+		{"uvm_macros", "`include \"uvm_macros.svh\"\nmodule t;\nendmodule", true},
+		{"reset_init", "module r;\n initial q = 0;\nendmodule", false},
+		{"finish_in_comment", "module r;\n initial q = 0; // $finish\nendmodule", false},
+		{"finish_in_string", "module r;\n initial $display(\"$finish\");\nendmodule", false},
+	}
+
+	for _, tc := range tests {
+		info := scanString(t, tc.name+".sv", tc.content)
+		if info.Testbench != tc.testbench {
+			t.Errorf("%s: Testbench = %v, expected %v", tc.name, info.Testbench, tc.testbench)
+		}
+	}
+}
+
+func TestDiscoverFilesVerilogContent(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		"bus_pkg.sv": "package bus_pkg;\n  parameter W = 8;\nendpackage",
+		"top.sv":     "module top;\n  import bus_pkg::*;\nendmodule",
+		"sim_top.sv": "module sim_top;\n  top dut ();\n  initial #100 $finish;\nendmodule",
+	})
+
+	files, err := DiscoverFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("DiscoverFiles failed: %v", err)
+	}
+
+	if got := strings.Join(relPaths(tmpDir, files.Package), " "); got != "bus_pkg.sv" {
+		t.Errorf("Package = %q, expected %q", got, "bus_pkg.sv")
+	}
+	if got := strings.Join(relPaths(tmpDir, files.RTL), " "); got != "top.sv" {
+		t.Errorf("RTL = %q, expected %q", got, "top.sv")
+	}
+	if got := strings.Join(relPaths(tmpDir, files.Behav), " "); got != "sim_top.sv" {
+		t.Errorf("Behav = %q, expected %q", got, "sim_top.sv")
+	}
+
+	if got := unitNames(files.Units); got != "package:bus_pkg module:sim_top module:top" {
+		t.Errorf("Units = %q", got)
+	}
+}
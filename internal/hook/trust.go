@@ -0,0 +1,95 @@
+package hook
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jakobsen/icw/internal/component"
+)
+
+// TrustFile returns the path to the file listing trusted component hooks
+func TrustFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".icw", "trusted_hooks")
+}
+
+// fingerprint identifies a hook by its source, its command and the content
+// of the files the command names in dir, such as ./setup, so a changed
+// command or script has to be trusted again
+func fingerprint(h component.Hook, dir string) string {
+	hash := sha256.New()
+	hash.Write([]byte(h.Command))
+	for _, word := range strings.Fields(h.Command) {
+		path := word
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(hash, "\x00%s\x00%d\x00", word, len(content))
+		hash.Write(content)
+	}
+	return h.Source + " " + hex.EncodeToString(hash.Sum(nil))
+}
+
+// IsTrusted reports whether a hook run in dir may run without asking.
+// Hooks from workspace.config are written by the user and always trusted.
+func IsTrusted(h component.Hook, dir string) bool {
+	if h.Source == "workspace.config" {
+		return true
+	}
+
+	file, err := os.Open(TrustFile())
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	want := fingerprint(h, dir)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Trust records a hook run in dir as trusted for future runs, as long as
+// neither its command nor the scripts it runs change
+func Trust(h component.Hook, dir string) error {
+	path := TrustFile()
+	if path == "" {
+		return fmt.Errorf("failed to get home directory")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create .icw directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open trusted hooks file: %w", err)
+	}
+
+	if _, err := fmt.Fprintln(file, fingerprint(h, dir)); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write trusted hooks file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write trusted hooks file: %w", err)
+	}
+	return nil
+}
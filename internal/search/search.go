@@ -0,0 +1,216 @@
+// Package search keeps a local index of the components in a repository and
+// matches queries against their names and metadata
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxAge is how long an index is used before it is rebuilt
+const MaxAge = 24 * time.Hour
+
+// IndexVersion is the format of the index written by Save. Indexes with an
+// older version lack fields and are rebuilt like stale ones.
+//
+//	1: name, type, description and owner (no version field)
+//	2: maturity, process nodes and license
+const IndexVersion = 2
+
+// Entry is an indexed component
+type Entry struct {
+	Name        string   `json:"name"` // e.g. "digital/spi_master"
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Maturity    string   `json:"maturity,omitempty"`
+	Nodes       []string `json:"nodes,omitempty"`
+	License     string   `json:"license,omitempty"`
+}
+
+// Index is the list of components in a repository at one point in time
+type Index struct {
+	Version int       `json:"version"`
+	Repo    string    `json:"repo"`
+	URL     string    `json:"url"`
+	Updated time.Time `json:"updated"`
+	Entries []Entry   `json:"entries"` // Sorted by name
+}
+
+// IndexPath returns the path of the cached index of a repository,
+// ~/.icw/cache/index-<repo>.json
+func IndexPath(repo string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	name := regexp.MustCompile(`[^A-Za-z0-9_.-]`).ReplaceAllString(repo, "_")
+	return filepath.Join(home, ".icw", "cache", "index-"+name+".json"), nil
+}
+
+// Load reads an index written by Save
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("invalid index %s: %w", path, err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index, replacing the file atomically so that a concurrent
+// search never reads a partial index
+func (idx *Index) Save(path string) error {
+	idx.Version = IndexVersion
+	sort.Slice(idx.Entries, func(i, j int) bool {
+		return idx.Entries[i].Name < idx.Entries[j].Name
+	})
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Stale reports whether the index is older than MaxAge or was written in an
+// older format
+func (idx *Index) Stale(now time.Time) bool {
+	return idx.Outdated() || now.Sub(idx.Updated) > MaxAge
+}
+
+// Outdated reports whether the index was written in an older format
+func (idx *Index) Outdated() bool {
+	return idx.Version < IndexVersion
+}
+
+// Fields that a query is matched against
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldOwner       = "owner"
+)
+
+// Query matches entries by name, description and owner, ignoring case
+type Query struct {
+	re       *regexp.Regexp
+	Types    []string // Only match these types; all if empty
+	Fields   []string // Only match these fields; all if empty
+	Maturity string   // Only match this maturity; all if empty
+	Node     string   // Only match entries supporting this process node; all if empty
+}
+
+// NewQuery compiles a query. Without regex the query is a glob pattern with
+// *, ? and [...] wildcards that must match a whole field, or the name
+// without its type; a query without wildcards matches anywhere in a field.
+func NewQuery(query string, regex bool) (*Query, error) {
+	expr := query
+	if !regex {
+		if !strings.ContainsAny(query, "*?[") {
+			query = "*" + query + "*"
+		}
+		var err error
+		expr, err = globToRegexp(query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", query, err)
+	}
+	return &Query{re: re}, nil
+}
+
+// Match returns the fields of an entry that match the query, or nil
+func (q *Query) Match(e Entry) []string {
+	if len(q.Types) > 0 && !slices.Contains(q.Types, e.Type) {
+		return nil
+	}
+	if q.Maturity != "" && !strings.EqualFold(q.Maturity, e.Maturity) {
+		return nil
+	}
+	if q.Node != "" && !slices.ContainsFunc(e.Nodes, func(node string) bool {
+		return strings.EqualFold(node, q.Node)
+	}) {
+		return nil
+	}
+
+	var matched []string
+	check := func(field string, values ...string) {
+		if len(q.Fields) > 0 && !slices.Contains(q.Fields, field) {
+			return
+		}
+		for _, value := range values {
+			if value != "" && q.re.MatchString(value) {
+				matched = append(matched, field)
+				return
+			}
+		}
+	}
+
+	check(FieldName, e.Name, strings.TrimPrefix(e.Name, e.Type+"/"))
+	check(FieldDescription, e.Description)
+	check(FieldOwner, e.Owner)
+	return matched
+}
+
+// Search returns the entries matching the query in name order
+func (idx *Index) Search(q *Query) []Entry {
+	var results []Entry
+	for _, e := range idx.Entries {
+		if q.Match(e) != nil {
+			results = append(results, e)
+		}
+	}
+	return results
+}
+
+// globToRegexp converts a glob pattern to an anchored regular expression
+func globToRegexp(glob string) (string, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return "", fmt.Errorf("invalid pattern %q: missing ]", glob)
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String(), nil
+}
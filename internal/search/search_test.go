@@ -0,0 +1,144 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testIndex() *Index {
+	return &Index{Repo: "icworks", Updated: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Entries: []Entry{
+		{Name: "analog/bandgap", Type: "analog", Description: "Bandgap reference, 1.2 V", Owner: "mhansen", Maturity: "silicon-proven", Nodes: []string{"tsmc28", "gf22"}},
+		{Name: "digital/spi_master", Type: "digital", Description: "SPI master with configurable CPOL/CPHA", Owner: "jdoe", Maturity: "qualified", Nodes: []string{"tsmc28"}},
+		{Name: "digital/spi_slave", Type: "digital", Owner: "jdoe", Maturity: "experimental"},
+		{Name: "digital/uart", Type: "digital", Description: "UART with 16-byte FIFO"},
+		{Name: "setup/analog", Type: "setup"},
+	}}
+}
+
+func names(entries []Entry) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+func TestSearch(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		query string
+		regex bool
+		types []string
+		want  []string
+	}{
+		{"spi", false, nil, []string{"digital/spi_master", "digital/spi_slave"}},
+		{"spi_*", false, nil, []string{"digital/spi_master", "digital/spi_slave"}},
+		{"digital/spi_?a*", false, nil, []string{"digital/spi_master"}},
+		{"*fifo*", false, nil, []string{"digital/uart"}},
+		{"FIFO", false, nil, []string{"digital/uart"}},
+		{"JDOE", false, nil, []string{"digital/spi_master", "digital/spi_slave"}},
+		{"analog", false, nil, []string{"analog/bandgap", "setup/analog"}},
+		{"analog", false, []string{"setup"}, []string{"setup/analog"}},
+		{"spi_[ms]*", false, nil, []string{"digital/spi_master", "digital/spi_slave"}},
+		{"spi_[!m]*", false, nil, []string{"digital/spi_slave"}},
+		{`^digital/(uart|spi_slave)$`, true, nil, []string{"digital/spi_slave", "digital/uart"}},
+		{`\d+\.\d+ V`, true, nil, []string{"analog/bandgap"}},
+		{"usb", false, nil, nil},
+	}
+
+	for _, tt := range tests {
+		q, err := NewQuery(tt.query, tt.regex)
+		if err != nil {
+			t.Fatalf("NewQuery(%q) failed: %v", tt.query, err)
+		}
+		q.Types = tt.types
+		if got := names(idx.Search(q)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := NewQuery("spi_[", false); err == nil {
+		t.Error("Expected error for unterminated class")
+	}
+	if _, err := NewQuery("(spi", true); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
+
+func TestMatchFields(t *testing.T) {
+	q, _ := NewQuery("jdoe", false)
+	if got := q.Match(testIndex().Entries[1]); !reflect.DeepEqual(got, []string{FieldOwner}) {
+		t.Errorf("Expected owner match, got %v", got)
+	}
+
+	q.Fields = []string{FieldName, FieldDescription}
+	if got := q.Match(testIndex().Entries[1]); got != nil {
+		t.Errorf("Owner must not be matched, got %v", got)
+	}
+}
+
+func TestMatchMaturityAndNode(t *testing.T) {
+	idx := testIndex()
+
+	tests := []struct {
+		maturity string
+		node     string
+		want     []string
+	}{
+		{"qualified", "", []string{"digital/spi_master"}},
+		{"Silicon-Proven", "", []string{"analog/bandgap"}},
+		{"", "tsmc28", []string{"analog/bandgap", "digital/spi_master"}},
+		{"", "GF22", []string{"analog/bandgap"}},
+		{"experimental", "tsmc28", nil},
+	}
+
+	for _, tt := range tests {
+		q, _ := NewQuery("*", false)
+		q.Maturity = tt.maturity
+		q.Node = tt.node
+		if got := names(idx.Search(q)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(maturity=%q, node=%q) = %v, want %v", tt.maturity, tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "index-icworks.json")
+	idx := testIndex()
+	idx.Entries[0], idx.Entries[4] = idx.Entries[4], idx.Entries[0]
+
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(names(loaded.Entries), names(testIndex().Entries)) {
+		t.Errorf("Entries not sorted by name: %v", names(loaded.Entries))
+	}
+	if !loaded.Updated.Equal(idx.Updated) {
+		t.Errorf("Updated time changed: %v", loaded.Updated)
+	}
+
+	if !loaded.Stale(idx.Updated.Add(25*time.Hour)) || loaded.Stale(idx.Updated.Add(time.Hour)) {
+		t.Error("Unexpected staleness")
+	}
+	if loaded.Version != IndexVersion {
+		t.Errorf("Version = %d, want %d", loaded.Version, IndexVersion)
+	}
+
+	// Indexes written before maturity and nodes were indexed have no version
+	os.WriteFile(path, []byte(`{"repo":"icworks","updated":"2026-03-01T12:00:00Z","entries":[]}`), 0644)
+	old, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of old index failed: %v", err)
+	}
+	if !old.Outdated() || !old.Stale(old.Updated.Add(time.Hour)) {
+		t.Error("Index without version should be outdated and stale")
+	}
+}
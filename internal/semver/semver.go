@@ -0,0 +1,202 @@
+// Package semver orders tag names as semantic versions, falling back to a
+// natural sort for tags that are not versions
+package semver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a tag name parsed as a semantic version. Tags like "v1", "1.2"
+// and "V1.2.3-rc.1+build5" are accepted; missing parts are zero.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string // e.g. "rc.1"; empty for a release
+	Build               string // Ignored when comparing
+}
+
+// Parse parses a tag name as a version
+func Parse(tag string) (Version, bool) {
+	var v Version
+	s := strings.TrimPrefix(strings.TrimPrefix(tag, "v"), "V")
+
+	s, v.Build, _ = strings.Cut(s, "+")
+	s, pre, found := strings.Cut(s, "-")
+	if found && pre == "" {
+		return Version{}, false
+	}
+	v.Prerelease = pre
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, false
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		if !isNumber(part) {
+			return Version{}, false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, false
+		}
+		*numbers[i] = n
+	}
+	return v, true
+}
+
+// IsPrerelease reports whether the version is a pre-release such as 1.0-rc1
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare returns -1, 0 or 1 if v is older than, equal to or newer than w.
+// A pre-release is older than the release of the same version.
+func (v Version) Compare(w Version) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	switch {
+	case v.Prerelease == w.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case w.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, w.Prerelease)
+}
+
+// comparePrerelease compares dot-separated pre-release identifiers: numbers
+// numerically, other identifiers naturally, and numbers before the others
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		aNum, bNum := isNumber(as[i]), isNumber(bs[i])
+		switch {
+		case aNum && !bNum:
+			return -1
+		case !aNum && bNum:
+			return 1
+		}
+		if c := Natural(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+// Compare orders two tag names. Versions are compared as versions and are
+// newer than tags that are not versions, which are ordered naturally.
+func Compare(a, b string) int {
+	va, aok := Parse(a)
+	vb, bok := Parse(b)
+	switch {
+	case aok && bok:
+		if c := va.Compare(vb); c != 0 {
+			return c
+		}
+		return Natural(a, b) // e.g. "v1.0" and "1.0.0"
+	case aok:
+		return 1
+	case bok:
+		return -1
+	}
+	return Natural(a, b)
+}
+
+// Sort sorts tag names from oldest to newest
+func Sort(tags []string) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		return Compare(tags[i], tags[j]) < 0
+	})
+}
+
+// Latest returns the newest release among the tags: the highest version that
+// is not a pre-release, else the highest pre-release, else the last tag in
+// natural order. Returns "" if there are no tags.
+func Latest(tags []string) string {
+	latest := ""
+	latestRelease := ""
+	for _, tag := range tags {
+		if latest == "" || Compare(tag, latest) > 0 {
+			latest = tag
+		}
+		if v, ok := Parse(tag); ok && !v.IsPrerelease() {
+			if latestRelease == "" || Compare(tag, latestRelease) > 0 {
+				latestRelease = tag
+			}
+		}
+	}
+	if latestRelease != "" {
+		return latestRelease
+	}
+	return latest
+}
+
+// Natural compares two strings with runs of digits compared as numbers, so
+// "build9" sorts before "build10"
+func Natural(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, restA := digitRun(a)
+			nb, restB := digitRun(b)
+
+			// Compare the numbers without leading zeros by length, then digits
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return sign(len(ta) - len(tb))
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			a, b = restA, restB
+			continue
+		}
+
+		if a[0] != b[0] {
+			return sign(int(a[0]) - int(b[0]))
+		}
+		a, b = a[1:], b[1:]
+	}
+	return sign(len(a) - len(b))
+}
+
+// digitRun splits s after its leading digits
+func digitRun(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Version
+		ok   bool
+	}{
+		{"v1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, true},
+		{"1.2", Version{Major: 1, Minor: 2}, true},
+		{"V2", Version{Major: 2}, true},
+		{"v1.0.0-rc.1+build-5", Version{Major: 1, Prerelease: "rc.1", Build: "build-5"}, true},
+		{"v1.10-beta", Version{Major: 1, Minor: 10, Prerelease: "beta"}, true},
+		{"v1.0-", Version{}, false},
+		{"v1.2.3.4", Version{}, false},
+		{"release_2024", Version{}, false},
+		{"v1..2", Version{}, false},
+		{"", Version{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Parse(tt.tag)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v, %v", tt.tag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	tags := []string{"v1.10", "v1.9", "v1.0-rc2", "v1.0", "snapshot_10", "v1.0-rc10", "snapshot_9", "v2.0.0-beta", "v1.0-rc.1", "v1.9.1"}
+	Sort(tags)
+
+	want := []string{"snapshot_9", "snapshot_10", "v1.0-rc.1", "v1.0-rc2", "v1.0-rc10", "v1.0", "v1.9", "v1.9.1", "v1.10", "v2.0.0-beta"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("Sort = %v, want %v", tags, want)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{[]string{"v1.9", "v1.10", "v2.0-rc1"}, "v1.10"},
+		{[]string{"v2.0-rc1", "v2.0-rc2"}, "v2.0-rc2"},
+		{[]string{"build9", "build10"}, "build10"},
+		{[]string{"build10", "v0.1"}, "v0.1"},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Latest(tt.tags); got != tt.want {
+			t.Errorf("Latest(%v) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestNatural(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"build9", "build10", -1},
+		{"build010", "build10", 0},
+		{"a", "b", -1},
+		{"abc", "ab", 1},
+		{"x2y", "x2z", -1},
+	}
+
+	for _, tt := range tests {
+		if got := Natural(tt.a, tt.b); got != tt.want {
+			t.Errorf("Natural(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
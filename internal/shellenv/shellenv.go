@@ -0,0 +1,118 @@
+// Package shellenv formats environment variables as shell commands
+package shellenv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Shells lists the supported output formats
+var Shells = []string{"bash", "zsh", "csh", "fish", "json"}
+
+// Var is a single environment variable
+type Var struct {
+	Name  string
+	Value string
+}
+
+// Name turns a component name such as "digital/top" into a variable name ("DIGITAL_TOP")
+func Name(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	name := b.String()
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// Expand replaces $NAME and ${NAME} in value using lookup.
+// References lookup does not know are kept as they are.
+func Expand(value string, lookup func(string) (string, bool)) string {
+	return os.Expand(value, func(name string) string {
+		if v, ok := lookup(name); ok {
+			return v
+		}
+		return "${" + name + "}"
+	})
+}
+
+// Format returns the commands that set vars in the given shell, or a JSON object
+func Format(shell string, vars []Var) (string, error) {
+	var b strings.Builder
+
+	switch shell {
+	case "bash", "zsh", "sh":
+		for _, v := range vars {
+			fmt.Fprintf(&b, "export %s=%s\n", v.Name, quotePOSIX(v.Value))
+		}
+	case "csh", "tcsh":
+		for _, v := range vars {
+			fmt.Fprintf(&b, "setenv %s %s;\n", v.Name, quoteCsh(v.Value))
+		}
+	case "fish":
+		for _, v := range vars {
+			fmt.Fprintf(&b, "set -gx %s %s;\n", v.Name, quoteFish(v.Value))
+		}
+	case "json":
+		return formatJSON(vars)
+	default:
+		return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(Shells, ", "))
+	}
+
+	return b.String(), nil
+}
+
+// quotePOSIX quotes a value for sh-compatible shells
+func quotePOSIX(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// quoteCsh quotes a value for csh. History expansion applies inside
+// single quotes as well, so ! is escaped separately.
+func quoteCsh(value string) string {
+	value = strings.ReplaceAll(value, "'", `'\''`)
+	value = strings.ReplaceAll(value, "!", `'\!'`)
+	return "'" + value + "'"
+}
+
+// quoteFish quotes a value for fish, where \ and ' are escaped inside single quotes
+func quoteFish(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "'", `\'`)
+	return "'" + value + "'"
+}
+
+// formatJSON writes vars as a JSON object, keeping their order
+func formatJSON(vars []Var) (string, error) {
+	var b bytes.Buffer
+	b.WriteString("{")
+	for i, v := range vars {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		name, err := json.Marshal(v.Name)
+		if err != nil {
+			return "", err
+		}
+		value, err := json.Marshal(v.Value)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\n  %s: %s", name, value)
+	}
+	if len(vars) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
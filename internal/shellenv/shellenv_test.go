@@ -0,0 +1,97 @@
+package shellenv
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+	tests := map[string]string{
+		"digital/top":        "DIGITAL_TOP",
+		"analog/bias-gen":    "ANALOG_BIAS_GEN",
+		"process/sky130A":    "PROCESS_SKY130A",
+		"3v3_io":             "_3V3_IO",
+		"setup/analog.tools": "SETUP_ANALOG_TOOLS",
+	}
+	for in, expected := range tests {
+		if got := Name(in); got != expected {
+			t.Errorf("Name(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"ICW_ROOT": "/work/ws", "ICW_COMPONENT_PATH": "/work/ws/process/sky130A"}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	tests := map[string]string{
+		"$ICW_COMPONENT_PATH/pdk": "/work/ws/process/sky130A/pdk",
+		"${ICW_ROOT}/sim":         "/work/ws/sim",
+		"$UNKNOWN/bin":            "${UNKNOWN}/bin",
+		"plain":                   "plain",
+	}
+	for in, expected := range tests {
+		if got := Expand(in, lookup); got != expected {
+			t.Errorf("Expand(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	vars := []Var{
+		{Name: "ICW_ROOT", Value: "/work/ws"},
+		{Name: "MSG", Value: "it's done!"},
+	}
+
+	tests := map[string]string{
+		"bash": "export ICW_ROOT='/work/ws'\nexport MSG='it'\\''s done!'\n",
+		"zsh":  "export ICW_ROOT='/work/ws'\nexport MSG='it'\\''s done!'\n",
+		"csh":  "setenv ICW_ROOT '/work/ws';\nsetenv MSG 'it'\\''s done'\\!'';\n",
+		"fish": "set -gx ICW_ROOT '/work/ws';\nset -gx MSG 'it\\'s done!';\n",
+	}
+	for shell, expected := range tests {
+		got, err := Format(shell, vars)
+		if err != nil {
+			t.Fatalf("Format(%s) failed: %v", shell, err)
+		}
+		if got != expected {
+			t.Errorf("Format(%s) =\n%s\nexpected\n%s", shell, got, expected)
+		}
+	}
+
+	if _, err := Format("powershell", vars); err == nil {
+		t.Error("Expected error for unsupported shell")
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	vars := []Var{
+		{Name: "B", Value: `quote " and \ backslash`},
+		{Name: "A", Value: "/work/ws"},
+	}
+
+	out, err := Format("json", vars)
+	if err != nil {
+		t.Fatalf("Format(json) failed: %v", err)
+	}
+
+	var decoded map[string]string
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out)
+	}
+	if decoded["B"] != vars[0].Value || decoded["A"] != vars[1].Value {
+		t.Errorf("Unexpected values: %v", decoded)
+	}
+
+	// Declaration order is kept
+	if out != "{\n  \"B\": \"quote \\\" and \\\\ backslash\",\n  \"A\": \"/work/ws\"\n}\n" {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	if out, _ := Format("json", nil); out != "{}\n" {
+		t.Errorf("Expected empty object, got %q", out)
+	}
+}
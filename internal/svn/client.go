@@ -1,12 +1,17 @@
 package svn
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
+	"strconv"
 	"strings"
 
 	"github.com/jakobsen/icw/internal/auth"
+	"github.com/jakobsen/icw/internal/component"
+	"github.com/jakobsen/icw/internal/semver"
 )
 
 // Client represents an SVN client
@@ -77,10 +82,26 @@ func NewClientWithConfig(repo, svnURL string) (*Client, error) {
 	}, nil
 }
 
+// ComponentURL returns the URL of a branch or tag of a component,
+// e.g. svn://anyvej11.dk/repo/components/digital/spi/trunk
+func (c *Client) ComponentURL(componentPath, branch string) string {
+	return fmt.Sprintf("%s/%s/components/%s/%s", c.URL, c.Repo, componentPath, branch)
+}
+
+// BranchFromURL returns the branch or tag of a component that url points at,
+// including nested branches such as branches/dev/foo. Returns false if url is
+// not below the component in this repository.
+func (c *Client) BranchFromURL(componentPath, url string) (string, bool) {
+	branch, ok := strings.CutPrefix(url, c.ComponentURL(componentPath, ""))
+	if !ok || branch == "" {
+		return "", false
+	}
+	return branch, true
+}
+
 // Checkout checks out a component from SVN
 func (c *Client) Checkout(componentPath, branch, destPath string) error {
-	// Build SVN URL: svn://anyvej11.dk/repo/components/path/branch
-	svnURL := fmt.Sprintf("%s/%s/components/%s/%s", c.URL, c.Repo, componentPath, branch)
+	svnURL := c.ComponentURL(componentPath, branch)
 
 	// Run svn checkout
 	cmd := exec.Command("svn", "checkout", svnURL, destPath, "--username", c.Username)
@@ -107,6 +128,52 @@ func (c *Client) Update(path string) error {
 	return nil
 }
 
+// Commit commits all local changes in a working copy and returns the new revision
+func (c *Client) Commit(path, message string) (string, error) {
+	cmd := exec.Command("svn", "commit", path, "-m", message, "--username", c.Username)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("svn commit failed: %w\n%s", err, output)
+	}
+
+	// Output ends with: Committed revision 1234.
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Committed revision ") {
+			return strings.TrimSuffix(strings.TrimPrefix(line, "Committed revision "), "."), nil
+		}
+	}
+
+	return "", nil
+}
+
+// Switch moves an existing working copy to another branch or tag of the component
+func (c *Client) Switch(componentPath, branch, destPath string) error {
+	svnURL := c.ComponentURL(componentPath, branch)
+
+	cmd := exec.Command("svn", "switch", svnURL, destPath, "--username", c.Username)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("svn switch failed: %w", err)
+	}
+
+	return nil
+}
+
+// Diff returns the local changes of a working copy as a unified diff.
+// Paths in the diff are relative to the working copy root.
+func (c *Client) Diff(path string) (string, error) {
+	cmd := exec.Command("svn", "diff", ".", "--username", c.Username)
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("svn diff failed: %w", err)
+	}
+
+	return string(output), nil
+}
+
 // Status returns the status of a working copy
 func (c *Client) Status(path string) (string, error) {
 	cmd := exec.Command("svn", "status", path, "--username", c.Username)
@@ -129,17 +196,114 @@ func (c *Client) Info(path string) (string, error) {
 	return string(output), nil
 }
 
-// Cat reads a file directly from the repository without checking it out
+// WorkingCopyURL returns the repository URL a working copy is checked out from
+func (c *Client) WorkingCopyURL(path string) (string, error) {
+	output, err := c.Info(path)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "URL:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "URL:")), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not determine URL from svn info")
+}
+
+// Revision returns the base revision of a working copy
+func (c *Client) Revision(path string) (int, error) {
+	cmd := exec.Command("svn", "info", "--show-item", "revision", path, "--username", c.Username)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("svn info failed: %w", err)
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
+// OutdatedRevisions returns the number of revisions committed to the working
+// copy's repository URL since the working copy was last updated
+func (c *Client) OutdatedRevisions(path string) (int, error) {
+	base, err := c.Revision(path)
+	if err != nil {
+		return 0, err
+	}
+
+	url, err := c.WorkingCopyURL(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Last revision that changed anything below the URL on the server
+	args := append([]string{"info", "--show-item", "last-changed-revision", url}, c.buildAuthArgs()...)
+	output, err := exec.Command("svn", args...).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("svn info failed: %w\n%s", err, output)
+	}
+	head, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected svn info output: %s", output)
+	}
+
+	if head <= base {
+		return 0, nil
+	}
+
+	// Count the commits between the working copy and the server
+	args = append([]string{"log", "-q", "-r", fmt.Sprintf("%d:%d", base+1, head), url}, c.buildAuthArgs()...)
+	output, err = exec.Command("svn", args...).CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("svn log failed: %w\n%s", err, output)
+	}
+
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "r") {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Relocate rewrites the repository URL prefix of a working copy (svn relocate)
+func (c *Client) Relocate(fromPrefix, toPrefix, path string) error {
+	cmd := exec.Command("svn", "relocate", fromPrefix, toPrefix, path, "--username", c.Username)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("svn relocate failed: %w\n%s", err, output)
+	}
+
+	return nil
+}
+
+// ErrNotFound is returned when a file or path does not exist in the repository
+var ErrNotFound = errors.New("not found in repository")
+
+// notFoundCodes are the svn error codes for paths that do not exist
+var notFoundCodes = []string{"E160013", "W160013", "E200009", "E170000"}
+
+// Cat reads a file directly from the repository without checking it out.
+// A missing file is reported as ErrNotFound, so it can be told apart from
+// authentication and network errors.
 func (c *Client) Cat(componentPath, branch, filename string) (string, error) {
 	// Construct URL to the file in the repository
 	url := fmt.Sprintf("%s/%s/components/%s/%s/%s", c.URL, c.Repo, componentPath, branch, filename)
 
 	args := append([]string{"cat", url}, c.buildAuthArgs()...)
 	cmd := exec.Command("svn", args...)
-	output, err := cmd.CombinedOutput()
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
 	if err != nil {
-		// File might not exist, which is OK for depend.config
-		return "", fmt.Errorf("svn cat failed: %w", err)
+		for _, code := range notFoundCodes {
+			if strings.Contains(stderr.String(), code) {
+				return "", fmt.Errorf("%s/%s/%s: %w", componentPath, branch, filename, ErrNotFound)
+			}
+		}
+		return "", fmt.Errorf("svn cat failed: %w\n%s", err, strings.TrimSpace(stderr.String()))
 	}
 
 	return string(output), nil
@@ -296,7 +460,7 @@ func (c *Client) ListBranches(componentPath string) ([]string, error) {
 	return branches, nil
 }
 
-// ListTags lists all tags for a component
+// ListTags lists all tags for a component, oldest version first
 func (c *Client) ListTags(componentPath string) ([]string, error) {
 	tagsURL := fmt.Sprintf("%s/%s/components/%s/tags", c.URL, c.Repo, componentPath)
 	args := append([]string{"list", tagsURL}, c.buildAuthArgs()...)
@@ -316,6 +480,7 @@ func (c *Client) ListTags(componentPath string) ([]string, error) {
 		}
 	}
 
+	semver.Sort(tags)
 	return tags, nil
 }
 
@@ -324,7 +489,7 @@ type ComponentInfo struct {
 	Path     string
 	HasTrunk bool
 	Branches []string
-	Tags     []string
+	Tags     []string // Oldest version first
 }
 
 // GetComponentInfo retrieves detailed information about a component
@@ -357,7 +522,27 @@ func (c *Client) GetComponentInfo(componentPath string) (*ComponentInfo, error)
 	return info, nil
 }
 
-// FindComponentsByPattern finds components matching a glob pattern
+// ListTypesError reports component types that could not be listed, e.g.
+// because the repository has no process/ directory. The components of the
+// other types are returned along with it.
+type ListTypesError struct {
+	Errs map[string]error // By component type
+}
+
+func (e *ListTypesError) Error() string {
+	var msgs []string
+	for _, typ := range component.RepositoryTypes {
+		if err, ok := e.Errs[string(typ)]; ok {
+			msgs = append(msgs, fmt.Sprintf("%s: %v", typ, err))
+		}
+	}
+	return "could not list " + strings.Join(msgs, "; ")
+}
+
+// FindComponentsByPattern finds components matching a glob pattern of the
+// form type/name. Both parts may use *, ? and [...] wildcards, so "*/spi*"
+// searches all types. Types that cannot be listed are skipped and reported
+// with a *ListTypesError.
 func (c *Client) FindComponentsByPattern(pattern string) ([]string, error) {
 	// Split pattern into type and name parts
 	// e.g., "digital/dig*" -> type="digital", namePattern="dig*"
@@ -366,58 +551,51 @@ func (c *Client) FindComponentsByPattern(pattern string) ([]string, error) {
 		return nil, fmt.Errorf("pattern must be in format type/pattern (e.g., digital/dig*)")
 	}
 
-	componentType := parts[0]
+	typePattern := parts[0]
 	namePattern := parts[1]
-
-	// Get all components of the specified type
-	components, err := c.ListComponentsByType(componentType)
-	if err != nil {
-		return nil, err
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
 	}
 
-	// Filter components matching the pattern
 	var matches []string
-	for _, comp := range components {
-		// Extract just the component name (after type/)
-		compParts := strings.SplitN(comp, "/", 2)
-		if len(compParts) == 2 {
-			compName := compParts[1]
-			// Simple glob matching (* at beginning, end, or both)
-			if matchGlob(compName, namePattern) {
+	failed := make(map[string]error)
+	for _, typ := range component.RepositoryTypes {
+		componentType := string(typ)
+		if !matchGlob(componentType, typePattern) {
+			continue
+		}
+
+		// Get all components of the type
+		components, err := c.ListComponentsByType(componentType)
+		if err != nil {
+			failed[componentType] = err
+			continue
+		}
+
+		// Filter components matching the pattern
+		for _, comp := range components {
+			// Extract just the component name (after type/)
+			compParts := strings.SplitN(comp, "/", 2)
+			if len(compParts) == 2 && matchGlob(compParts[1], namePattern) {
 				matches = append(matches, comp)
 			}
 		}
 	}
 
+	if len(failed) > 0 {
+		return matches, &ListTypesError{Errs: failed}
+	}
 	return matches, nil
 }
 
-// matchGlob performs simple glob pattern matching
-// Supports: *, prefix*, *suffix, *middle*, exact
-func matchGlob(name, pattern string) bool {
-	// No wildcards - exact match
-	if !strings.Contains(pattern, "*") {
-		return name == pattern
-	}
-
-	// Count wildcards
-	wildcardCount := strings.Count(pattern, "*")
-
-	if wildcardCount == 1 {
-		if strings.HasPrefix(pattern, "*") {
-			// *suffix
-			suffix := strings.TrimPrefix(pattern, "*")
-			return strings.HasSuffix(name, suffix)
-		} else if strings.HasSuffix(pattern, "*") {
-			// prefix*
-			prefix := strings.TrimSuffix(pattern, "*")
-			return strings.HasPrefix(name, prefix)
-		}
-	} else if wildcardCount == 2 && strings.HasPrefix(pattern, "*") && strings.HasSuffix(pattern, "*") {
-		// *middle*
-		middle := strings.Trim(pattern, "*")
-		return strings.Contains(name, middle)
-	}
+// IsPattern reports whether name contains glob wildcards
+func IsPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
 
-	return false
+// matchGlob reports whether name matches a glob pattern with *, ? and [...]
+// wildcards. Malformed patterns match nothing.
+func matchGlob(name, pattern string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
 }
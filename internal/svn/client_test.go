@@ -0,0 +1,80 @@
+package svn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    bool
+	}{
+		{"spi_master", "spi_master", true},
+		{"spi_master", "spi", false},
+		{"spi_master", "spi*", true},
+		{"spi_master", "*master", true},
+		{"spi_master", "*_mas*", true},
+		{"dig_top_cp3", "dig*cp3", true},
+		{"dig_top_cp3", "dig_*_cp?", true},
+		{"dig_top_cp4", "*cp[34]", true},
+		{"dig_top_cp5", "*cp[34]", false},
+		{"uart", "*a*r*", true},
+		{"uart", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.name, tt.pattern); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.name, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestIsPattern(t *testing.T) {
+	for name, want := range map[string]bool{
+		"digital/spi":    false,
+		"digital/spi*":   true,
+		"*/spi":          true,
+		"digital/cp[34]": true,
+		"digital/spi_?":  true,
+	} {
+		if got := IsPattern(name); got != want {
+			t.Errorf("IsPattern(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestListTypesError(t *testing.T) {
+	err := &ListTypesError{Errs: map[string]error{
+		"process": errors.New("path not found"),
+		"analog":  errors.New("access denied"),
+	}}
+
+	want := "could not list analog: access denied; process: path not found"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestBranchFromURL(t *testing.T) {
+	c := &Client{URL: "svn://anyvej11.dk", Repo: "icworks"}
+
+	tests := []struct {
+		url    string
+		branch string
+		ok     bool
+	}{
+		{"svn://anyvej11.dk/icworks/components/digital/spi/trunk", "trunk", true},
+		{"svn://anyvej11.dk/icworks/components/digital/spi/tags/v1.2", "tags/v1.2", true},
+		{"svn://anyvej11.dk/icworks/components/digital/spi/branches/dev/foo", "branches/dev/foo", true},
+		{"svn://anyvej11.dk/icworks/components/digital/spi_master/trunk", "", false},
+		{"svn+ssh://other/icworks/components/digital/spi/trunk", "", false},
+	}
+	for _, tt := range tests {
+		branch, ok := c.BranchFromURL("digital/spi", tt.url)
+		if branch != tt.branch || ok != tt.ok {
+			t.Errorf("BranchFromURL(%q) = %q, %v, expected %q, %v", tt.url, branch, ok, tt.branch, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package svn
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ArtefactPatterns matches files produced by Cadence tools and simulators
+// that should normally be ignored rather than added to the repository. Only
+// names the tools write are listed; generic names such as work or *.log may
+// be design files.
+var ArtefactPatterns = []string{
+	// Cadence Virtuoso / OpenAccess
+	"*.cdslck", "*.cdslck.*", "*.oa-", "*.oa~", ".cadence", "panic*.log", "CDS.log*", "libManager.log*",
+	".skillide.*", "*.abstract.*",
+	// Spectre / analog simulation
+	"*.raw", "*.psf", "*.ahdlSimDB", "spectre.log", "spectre.out",
+	// Xcelium / Incisive
+	"INCA_libs", "xcelium.d", "*.shm", "*.trn", "*.dsn", "irun.history", "xrun.history", "irun.log",
+	"xrun.log", "irun.key", "xrun.key",
+	// Questa / ModelSim
+	"transcript", "*.wlf", "modelsim.ini",
+	// VCS / Verilator / Icarus
+	"csrc", "simv", "simv.daidir", "ucli.key", "vc_hdrs.h", "obj_dir", "*.vvp",
+	// Waveforms
+	"*.vcd", "*.fst", "*.fsdb", "*.ghw",
+	// Editors
+	"*~", "*.swp", ".*.swp",
+}
+
+// IsArtefact reports whether a file name matches one of the ArtefactPatterns
+func IsArtefact(path string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range ArtefactPatterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// AddPath schedules an unversioned file or directory for addition
+func (c *Client) AddPath(path string) error {
+	cmd := exec.Command("svn", "add", path, "--username", c.Username)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("svn add failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// Revert discards all local changes of a file or directory and everything below it
+func (c *Client) Revert(path string) error {
+	return c.revert(path, "infinity")
+}
+
+// RevertEntry discards the change reported by a single status entry. Only an
+// added or replaced directory is reverted with its content; for a modified
+// directory the change is a property change, and the changes below it are
+// separate entries.
+func (c *Client) RevertEntry(entry StatusEntry) error {
+	depth := "empty"
+	if entry.Code == 'A' || entry.Code == 'R' {
+		if info, err := os.Stat(entry.Path); err == nil && info.IsDir() {
+			depth = "infinity"
+		}
+	}
+	return c.revert(entry.Path, depth)
+}
+
+func (c *Client) revert(path, depth string) error {
+	cmd := exec.Command("svn", "revert", "--depth", depth, path, "--username", c.Username)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("svn revert failed: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// AddIgnore appends a pattern to the svn:ignore property of a directory
+func (c *Client) AddIgnore(dir, pattern string) error {
+	// Missing property is not an error, it just gives empty output
+	cmd := exec.Command("svn", "propget", "svn:ignore", dir, "--username", c.Username)
+	output, _ := cmd.Output()
+
+	var patterns []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == pattern {
+			return nil // Already ignored
+		}
+		if line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	patterns = append(patterns, pattern)
+
+	cmd = exec.Command("svn", "propset", "svn:ignore", strings.Join(patterns, "\n"), dir, "--username", c.Username)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("svn propset failed: %w\n%s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package svn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogEntry is a commit as reported by svn log --xml
+type LogEntry struct {
+	Revision int       `xml:"revision,attr"`
+	Author   string    `xml:"author"`
+	Date     time.Time `xml:"date"`
+	Message  string    `xml:"msg"`
+	Paths    []LogPath `xml:"paths>path"` // Only with svn log -v
+}
+
+// LogPath is a path changed by a commit
+type LogPath struct {
+	Action       string `xml:"action,attr"` // A, M, D or R
+	Kind         string `xml:"kind,attr"`   // file or dir
+	CopyFromPath string `xml:"copyfrom-path,attr"`
+	CopyFromRev  int    `xml:"copyfrom-rev,attr"`
+	Path         string `xml:",chardata"` // Relative to the repository root
+}
+
+// Summary returns the first line of the commit message
+func (e LogEntry) Summary() string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(e.Message), "\n")
+	return strings.TrimSpace(summary)
+}
+
+// ParseLog parses the output of svn log --xml
+func ParseLog(data []byte) ([]LogEntry, error) {
+	var log struct {
+		Entries []LogEntry `xml:"logentry"`
+	}
+	if err := xml.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("invalid svn log output: %w", err)
+	}
+	return log.Entries, nil
+}
+
+// Log returns the commits below a branch of a component, newest first.
+// With verbose the changed paths are included.
+func (c *Client) Log(componentPath, branch string, verbose bool) ([]LogEntry, error) {
+	url := fmt.Sprintf("%s/%s/components/%s/%s", c.URL, c.Repo, componentPath, branch)
+
+	args := []string{"log", "--xml"}
+	if verbose {
+		args = append(args, "-v")
+	}
+	args = append(append(args, url), c.buildAuthArgs()...)
+
+	// Stderr is kept apart so that warnings do not end up in the XML
+	cmd := exec.Command("svn", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("svn log failed: %w\n%s", err, stderr.String())
+	}
+
+	return ParseLog(output)
+}
+
+// Tag is the creation of a tag, found in the log of the tags directory
+type Tag struct {
+	Name        string // e.g. "v1.2.0"
+	Revision    int    // Revision that created the tag
+	Author      string
+	Date        time.Time
+	Message     string
+	CopyFrom    string // Branch the tag was copied from, e.g. "trunk"
+	CopyFromRev int    // Revision of the branch the tag was copied from
+}
+
+// Branch returns the branch name of the tag as used in depend.config
+func (t Tag) Branch() string {
+	return "tags/" + t.Name
+}
+
+// TagsFromLog returns the tags of a component created in the given log of
+// its tags directory, oldest first. A tag that was deleted is dropped, and a
+// tag that was created again is reported with its latest creation.
+func TagsFromLog(entries []LogEntry, componentPath string) []Tag {
+	marker := "/" + componentPath + "/tags/"
+	tags := make(map[string]Tag)
+
+	// Apply the commits in revision order
+	sorted := append([]LogEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Revision < sorted[j].Revision })
+
+	for _, entry := range sorted {
+		for _, p := range entry.Paths {
+			idx := strings.Index(p.Path, marker)
+			if idx < 0 {
+				continue
+			}
+			name := p.Path[idx+len(marker):]
+			if name == "" || strings.Contains(name, "/") {
+				continue // A change inside a tag, not the tag itself
+			}
+
+			switch p.Action {
+			case "D":
+				delete(tags, name)
+			case "A", "R":
+				tag := Tag{
+					Name:        name,
+					Revision:    entry.Revision,
+					Author:      entry.Author,
+					Date:        entry.Date,
+					Message:     strings.TrimSpace(entry.Message),
+					CopyFromRev: p.CopyFromRev,
+				}
+				if from := strings.Index(p.CopyFromPath, "/"+componentPath+"/"); from >= 0 {
+					tag.CopyFrom = p.CopyFromPath[from+len(componentPath)+2:]
+				}
+				tags[name] = tag
+			}
+		}
+	}
+
+	var result []Tag
+	for _, tag := range tags {
+		result = append(result, tag)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Revision != result[j].Revision {
+			return result[i].Revision < result[j].Revision
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// CommitsBetween returns the commits with a revision in (from, to], newest
+// first. A to of 0 means no upper limit.
+func CommitsBetween(entries []LogEntry, from, to int) []LogEntry {
+	var result []LogEntry
+	for _, entry := range entries {
+		if entry.Revision > from && (to == 0 || entry.Revision <= to) {
+			result = append(result, entry)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Revision > result[j].Revision })
+	return result
+}
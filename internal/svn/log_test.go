@@ -0,0 +1,125 @@
+package svn
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const tagsLog = `<?xml version="1.0" encoding="UTF-8"?>
+<log>
+<logentry revision="142">
+<author>jdoe</author>
+<date>2026-05-04T09:12:45.123456Z</date>
+<paths>
+<path action="A" kind="dir" copyfrom-path="/components/digital/spi_master/trunk" copyfrom-rev="140">/components/digital/spi_master/tags/v1.1</path>
+</paths>
+<msg>Release 1.1
+Adds CPHA support</msg>
+</logentry>
+<logentry revision="120">
+<author>mhansen</author>
+<date>2026-04-01T10:00:00.000000Z</date>
+<paths>
+<path action="D" kind="dir">/components/digital/spi_master/tags/v0.9-broken</path>
+</paths>
+<msg>Remove broken tag</msg>
+</logentry>
+<logentry revision="118">
+<author>mhansen</author>
+<date>2026-03-30T15:30:00.000000Z</date>
+<paths>
+<path action="A" kind="dir" copyfrom-path="/components/digital/spi_master/trunk" copyfrom-rev="117">/components/digital/spi_master/tags/v0.9-broken</path>
+</paths>
+<msg>Tag 0.9</msg>
+</logentry>
+<logentry revision="110">
+<author>jdoe</author>
+<date>2026-03-02T08:00:00.000000Z</date>
+<paths>
+<path action="A" kind="dir" copyfrom-path="/components/digital/spi_master/branches/stable" copyfrom-rev="108">/components/digital/spi_master/tags/v1.0</path>
+<path action="M" kind="file">/components/digital/spi_master/tags/v1.0/depend.config</path>
+</paths>
+<msg>Release 1.0</msg>
+</logentry>
+<logentry revision="100">
+<author>jdoe</author>
+<date>2026-03-01T08:00:00.000000Z</date>
+<paths>
+<path action="A" kind="dir">/components/digital/spi_master/tags</path>
+</paths>
+<msg>Created component digital/spi_master</msg>
+</logentry>
+</log>
+`
+
+func TestParseLog(t *testing.T) {
+	entries, err := ParseLog([]byte(tagsLog))
+	if err != nil {
+		t.Fatalf("ParseLog failed: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if first.Revision != 142 || first.Author != "jdoe" {
+		t.Errorf("Unexpected entry: %+v", first)
+	}
+	if want := time.Date(2026, 5, 4, 9, 12, 45, 123456000, time.UTC); !first.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", first.Date, want)
+	}
+	if first.Summary() != "Release 1.1" {
+		t.Errorf("Summary = %q", first.Summary())
+	}
+	want := LogPath{Action: "A", Kind: "dir", CopyFromPath: "/components/digital/spi_master/trunk", CopyFromRev: 140, Path: "/components/digital/spi_master/tags/v1.1"}
+	if !reflect.DeepEqual(first.Paths, []LogPath{want}) {
+		t.Errorf("Paths = %+v", first.Paths)
+	}
+
+	if _, err := ParseLog([]byte("svn: E170013: Unable to connect")); err == nil {
+		t.Error("Expected error for non-XML output")
+	}
+}
+
+func TestTagsFromLog(t *testing.T) {
+	entries, _ := ParseLog([]byte(tagsLog))
+	tags := TagsFromLog(entries, "digital/spi_master")
+
+	if len(tags) != 2 {
+		t.Fatalf("Expected 2 tags, got %+v", tags)
+	}
+	if tags[0].Name != "v1.0" || tags[0].Revision != 110 || tags[0].CopyFrom != "branches/stable" || tags[0].CopyFromRev != 108 {
+		t.Errorf("Unexpected first tag: %+v", tags[0])
+	}
+	if tags[1].Name != "v1.1" || tags[1].CopyFrom != "trunk" || tags[1].Message != "Release 1.1\nAdds CPHA support" {
+		t.Errorf("Unexpected second tag: %+v", tags[1])
+	}
+	if tags[1].Branch() != "tags/v1.1" {
+		t.Errorf("Branch = %q", tags[1].Branch())
+	}
+
+	// Tags of other components are ignored
+	if tags := TagsFromLog(entries, "digital/spi"); len(tags) != 0 {
+		t.Errorf("Expected no tags, got %+v", tags)
+	}
+}
+
+func TestCommitsBetween(t *testing.T) {
+	entries := []LogEntry{{Revision: 101}, {Revision: 130}, {Revision: 115}, {Revision: 140}}
+
+	revisions := func(entries []LogEntry) []int {
+		var result []int
+		for _, e := range entries {
+			result = append(result, e.Revision)
+		}
+		return result
+	}
+
+	if got := revisions(CommitsBetween(entries, 115, 140)); !reflect.DeepEqual(got, []int{140, 130}) {
+		t.Errorf("CommitsBetween(115, 140) = %v", got)
+	}
+	if got := revisions(CommitsBetween(entries, 110, 0)); !reflect.DeepEqual(got, []int{140, 130, 115}) {
+		t.Errorf("CommitsBetween(110, 0) = %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package svn
+
+import "strings"
+
+// StatusEntry is a single item reported by svn status
+type StatusEntry struct {
+	Code byte   // First status column (M, A, D, ?, !, C, ...)
+	Path string // Path as printed by svn status
+}
+
+// IsUnversioned reports whether the entry is not under version control
+func (e StatusEntry) IsUnversioned() bool {
+	return e.Code == '?'
+}
+
+// IsModified reports whether the entry holds local changes that would be committed
+func (e StatusEntry) IsModified() bool {
+	switch e.Code {
+	case 'M', 'A', 'D', 'R', 'C', '~':
+		return true
+	}
+	return false
+}
+
+// ParseStatus parses the text output of svn status into entries.
+// Lines that do not describe a path (e.g. externals headers) are ignored.
+func ParseStatus(output string) []StatusEntry {
+	var entries []StatusEntry
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		// Status lines have 7 status columns, a space and then the path
+		if len(line) < 9 || line[7] != ' ' {
+			continue
+		}
+		if strings.HasPrefix(line, "Performing status") || strings.HasPrefix(line, "Status against") {
+			continue
+		}
+
+		path := strings.TrimSpace(line[8:])
+		if path == "" {
+			continue
+		}
+
+		code := line[0]
+		if code == 'X' {
+			// Directory only present because of an externals definition
+			continue
+		}
+		if code == ' ' {
+			// Property-only change is reported in the second column
+			if line[1] == ' ' {
+				continue
+			}
+			code = 'M'
+		}
+
+		entries = append(entries, StatusEntry{Code: code, Path: path})
+	}
+	return entries
+}
+
+// IsClean reports whether a working copy has neither local modifications
+// nor unversioned files
+func (c *Client) IsClean(path string) (bool, []StatusEntry, error) {
+	output, err := c.Status(path)
+	if err != nil {
+		return false, nil, err
+	}
+	entries := ParseStatus(output)
+	return len(entries) == 0, entries, nil
+}
@@ -0,0 +1,68 @@
+package svn
+
+import "testing"
+
+func TestParseStatus(t *testing.T) {
+	output := `M       /ws/digital/top/top.sv
+?       /ws/digital/top/waves.vcd
+ M      /ws/digital/top
+!       /ws/digital/top/old.v
+X       /ws/digital/top/ext
+
+Performing status on external item at '/ws/digital/top/ext':
+C       /ws/digital/top/ext/conflict.v
+`
+
+	entries := ParseStatus(output)
+
+	expected := []StatusEntry{
+		{Code: 'M', Path: "/ws/digital/top/top.sv"},
+		{Code: '?', Path: "/ws/digital/top/waves.vcd"},
+		{Code: 'M', Path: "/ws/digital/top"},
+		{Code: '!', Path: "/ws/digital/top/old.v"},
+		{Code: 'C', Path: "/ws/digital/top/ext/conflict.v"},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(expected), len(entries), entries)
+	}
+	for i, entry := range entries {
+		if entry != expected[i] {
+			t.Errorf("Entry %d: expected %c %s, got %c %s", i, expected[i].Code, expected[i].Path, entry.Code, entry.Path)
+		}
+	}
+
+	if !entries[0].IsModified() || entries[0].IsUnversioned() {
+		t.Error("Expected M entry to be modified")
+	}
+	if entries[1].IsModified() || !entries[1].IsUnversioned() {
+		t.Error("Expected ? entry to be unversioned")
+	}
+}
+
+func TestParseStatusEmpty(t *testing.T) {
+	if entries := ParseStatus(""); len(entries) != 0 {
+		t.Errorf("Expected no entries, got %d", len(entries))
+	}
+}
+
+func TestIsArtefact(t *testing.T) {
+	testCases := map[string]bool{
+		"/ws/analog/bias/bias/layout/layout.oa.cdslck": true,
+		"/ws/digital/top/sim/xcelium.d":                true,
+		"/ws/digital/top/waves.vcd":                    true,
+		"/ws/digital/top/INCA_libs":                    true,
+		"/ws/digital/top/sim/xrun.log":                 true,
+		"/ws/digital/top/work":                         false,
+		"/ws/digital/top/notes.log":                    false,
+		"/ws/digital/top/top.sv":                       false,
+		"/ws/digital/top/depend.config":                false,
+		"/ws/digital/top/rtl":                          false,
+	}
+
+	for path, expected := range testCases {
+		if got := IsArtefact(path); got != expected {
+			t.Errorf("IsArtefact(%s) = %v, expected %v", path, got, expected)
+		}
+	}
+}
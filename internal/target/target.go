@@ -0,0 +1,17 @@
+// Package target names the tool project formats written by icw export, so
+// that configuration can be checked without depending on the generators
+package target
+
+import "slices"
+
+// Names lists the supported export targets
+var Names = []string{"filelist", "verilator", "yosys", "openroad", "fusesoc", "edalize", "make", "tcl"}
+
+// PerComponent is the target writing one file per component instead of one
+// file for the project
+const PerComponent = "fusesoc"
+
+// IsSingleFile reports whether name is a target written as one file
+func IsSingleFile(name string) bool {
+	return slices.Contains(Names, name) && name != PerComponent
+}
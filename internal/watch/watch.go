@@ -0,0 +1,48 @@
+// Package watch reports changes to files in directory trees
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Op is the kind of change of an event
+type Op int
+
+const (
+	Create   Op = iota // File or directory created or moved in
+	Write              // File written and closed
+	Remove             // File or directory deleted or moved out
+	Overflow           // Events were lost; everything must be rescanned
+)
+
+// Event is a change of a file or directory
+type Event struct {
+	Path  string // Absolute path; empty for Overflow
+	Op    Op
+	IsDir bool
+}
+
+// subdirs returns dir and all directories below it, leaving out hidden
+// directories and directories for which skip returns true
+func subdirs(dir string, skip func(path string) bool) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path != dir && os.IsNotExist(err) {
+				return nil // Removed while walking
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && (strings.HasPrefix(d.Name(), ".") || (skip != nil && skip(path))) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs, err
+}
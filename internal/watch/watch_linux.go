@@ -0,0 +1,182 @@
+package watch
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchMask selects the inotify events that change the set of files or
+// their content. Writes are reported once the file is closed.
+const watchMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_CLOSE_WRITE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_ONLYDIR
+
+// Watcher watches directories with inotify. Each directory of a tree needs
+// its own watch, so new subdirectories must be added as they appear.
+type Watcher struct {
+	fd    int
+	dirs  map[int]string // Directory by watch descriptor
+	wds   map[string]int // Watch descriptor by directory
+	buf   []byte
+	queue []Event // Events read but not yet returned
+}
+
+// New creates a watcher without any watched directories
+func New() (*Watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+	return &Watcher{
+		fd:   fd,
+		dirs: make(map[int]string),
+		wds:  make(map[string]int),
+		buf:  make([]byte, 64*1024),
+	}, nil
+}
+
+// Add watches a single directory
+func (w *Watcher) Add(dir string) error {
+	dir = filepath.Clean(dir)
+	if _, ok := w.wds[dir]; ok {
+		return nil
+	}
+
+	wd, err := unix.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		if err == unix.ENOSPC {
+			return fmt.Errorf("failed to watch %s: inotify watch limit reached (see fs.inotify.max_user_watches)", dir)
+		}
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	w.dirs[wd] = dir
+	w.wds[dir] = wd
+	return nil
+}
+
+// AddTree watches dir and all directories below it. Hidden directories and
+// directories for which skip returns true are left out.
+func (w *Watcher) AddTree(dir string, skip func(path string) bool) error {
+	dirs, err := subdirs(dir, skip)
+	if err != nil {
+		return err
+	}
+	for _, d := range dirs {
+		if err := w.Add(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove stops watching dir and all directories below it
+func (w *Watcher) Remove(dir string) {
+	dir = filepath.Clean(dir)
+	for path, wd := range w.wds {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			unix.InotifyRmWatch(w.fd, uint32(wd))
+			delete(w.wds, path)
+			delete(w.dirs, wd)
+		}
+	}
+}
+
+// Len returns the number of watched directories
+func (w *Watcher) Len() int {
+	return len(w.wds)
+}
+
+// Wait returns the next events, waiting at most timeout. It returns no
+// events if nothing changed in time.
+func (w *Watcher) Wait(timeout time.Duration) ([]Event, error) {
+	if len(w.queue) == 0 {
+		fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(timeout.Milliseconds()))
+		if err == unix.EINTR {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for changes: %w", err)
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		if err := w.read(); err != nil {
+			return nil, err
+		}
+	}
+
+	events := w.queue
+	w.queue = nil
+	return events, nil
+}
+
+// read reads all pending inotify events into the queue
+func (w *Watcher) read() error {
+	for {
+		n, err := unix.Read(w.fd, w.buf)
+		if err == unix.EAGAIN || err == unix.EINTR {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read inotify events: %w", err)
+		}
+		if n < unix.SizeofInotifyEvent {
+			return nil
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&w.buf[offset]))
+			nameBytes := w.buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(raw.Len)]
+			offset += unix.SizeofInotifyEvent + int(raw.Len)
+
+			name := strings.TrimRight(string(nameBytes), "\x00")
+			w.handle(int(raw.Wd), raw.Mask, name)
+		}
+	}
+}
+
+// handle converts one inotify event and updates the watch tables
+func (w *Watcher) handle(wd int, mask uint32, name string) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		w.queue = append(w.queue, Event{Op: Overflow})
+		return
+	}
+
+	dir, ok := w.dirs[wd]
+	if !ok {
+		return
+	}
+	if mask&unix.IN_IGNORED != 0 {
+		// The directory was removed or its watch dropped
+		delete(w.dirs, wd)
+		delete(w.wds, dir)
+		return
+	}
+	if mask&unix.IN_DELETE_SELF != 0 {
+		return // Reported as a removal by the parent directory
+	}
+
+	event := Event{Path: filepath.Join(dir, name), IsDir: mask&unix.IN_ISDIR != 0}
+	switch {
+	case mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0:
+		event.Op = Create
+	case mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0:
+		event.Op = Remove
+		if event.IsDir {
+			w.Remove(event.Path)
+		}
+	default:
+		event.Op = Write
+	}
+	w.queue = append(w.queue, event)
+}
+
+// Close stops all watches
+func (w *Watcher) Close() error {
+	return unix.Close(w.fd)
+}
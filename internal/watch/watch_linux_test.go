@@ -0,0 +1,71 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitFor collects events until one matches or the timeout expires
+func waitFor(t *testing.T, w *Watcher, path string, op Op) Event {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		events, err := w.Wait(100 * time.Millisecond)
+		if err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+		for _, event := range events {
+			if event.Path == path && event.Op == op {
+				return event
+			}
+		}
+	}
+	t.Fatalf("No event %d for %s", op, path)
+	return Event{}
+}
+
+func TestWatcher(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.MkdirAll(filepath.Join(tmpDir, "rtl", "sub"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, ".svn"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "build"), 0755)
+
+	w, err := New()
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer w.Close()
+
+	skip := func(path string) bool { return filepath.Base(path) == "build" }
+	if err := w.AddTree(tmpDir, skip); err != nil {
+		t.Fatalf("AddTree failed: %v", err)
+	}
+	if w.Len() != 3 {
+		t.Errorf("Expected 3 watched directories (root, rtl, rtl/sub), got %d", w.Len())
+	}
+
+	file := filepath.Join(tmpDir, "rtl", "sub", "fifo.sv")
+	os.WriteFile(file, []byte("module fifo; endmodule"), 0644)
+	waitFor(t, w, file, Create)
+
+	os.Remove(file)
+	waitFor(t, w, file, Remove)
+
+	newDir := filepath.Join(tmpDir, "tb")
+	os.Mkdir(newDir, 0755)
+	if event := waitFor(t, w, newDir, Create); !event.IsDir {
+		t.Error("Expected a directory event")
+	}
+
+	os.RemoveAll(filepath.Join(tmpDir, "rtl"))
+	waitFor(t, w, filepath.Join(tmpDir, "rtl"), Remove)
+	if w.Len() != 1 {
+		t.Errorf("Removed directories must no longer be watched, %d left", w.Len())
+	}
+
+	if events, _ := w.Wait(10 * time.Millisecond); len(events) != 0 {
+		t.Errorf("Unexpected events: %+v", events)
+	}
+}
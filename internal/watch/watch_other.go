@@ -0,0 +1,23 @@
+//go:build !linux
+
+package watch
+
+import (
+	"fmt"
+	"time"
+)
+
+// Watcher is only implemented with inotify on Linux
+type Watcher struct{}
+
+// New reports that watching is not supported on this platform
+func New() (*Watcher, error) {
+	return nil, fmt.Errorf("watching files requires inotify, which is only available on Linux")
+}
+
+func (w *Watcher) Add(dir string) error                                  { return nil }
+func (w *Watcher) AddTree(dir string, skip func(path string) bool) error { return nil }
+func (w *Watcher) Remove(dir string)                                     {}
+func (w *Watcher) Len() int                                              { return 0 }
+func (w *Watcher) Wait(timeout time.Duration) ([]Event, error)           { return nil, nil }
+func (w *Watcher) Close() error                                          { return nil }